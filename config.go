@@ -0,0 +1,267 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	nanohatoled "nanohat-oled/ext"
+)
+
+const configPath = "/etc/NanoHatOLED/nanohat-oled.conf"
+
+// config holds daemon settings loaded from configPath
+type config struct {
+	oled     nanohatoled.Options
+	rotation int  // Screen rotation in degrees (0, 90, 180, 270)
+	invert   bool // Hardware display inversion
+
+	logo      *nanohatoled.ImageOptions // Logo conversion, nil keeps the plain threshold
+	infoFont  string                    // Font file for the system-info page, empty uses DejaVu 10pt
+	infoIcons bool                      // Show icons instead of labels on the system-info page
+
+	contrast    int // Daytime contrast, -1 keeps the controller default
+	dimContrast int // Contrast inside the dim window
+	dimStart    int // Dim window start in minutes after midnight, -1 disables
+	dimEnd      int // Dim window end in minutes after midnight
+	precharge   int // Raw pre-charge register value, -1 keeps the default
+	vcomh       int // Raw VCOMH register value, -1 keeps the default
+
+	sleepAfter    int           // Seconds without a button press before the display turns off, 0 never
+	idleDim       int           // Seconds before turning off spent at idleContrast, 0 skips the dim stage
+	idleContrast  int           // Contrast of the dim stage
+	shiftPixels   int           // How far the clock page moves against burn-in, 0 keeps it still
+	shiftInterval time.Duration // Time between clock page moves
+
+	buttonSource nanohatoled.ButtonSource // Simulated button input, nil reads the GPIO buttons
+	poweroff     bool                     // Power off after the shutdown confirmation, false only exits
+}
+
+// defaultConfig returns settings used when no config file is present
+func defaultConfig() *config {
+	return &config{
+		oled:        nanohatoled.DefaultOptions(),
+		contrast:    -1,
+		dimContrast: 0x10,
+		dimStart:    -1,
+		dimEnd:      -1,
+		precharge:   -1,
+		vcomh:       -1,
+		poweroff:    true,
+
+		sleepAfter:    pageSleep,
+		idleContrast:  0x01,
+		shiftPixels:   2,
+		shiftInterval: 3 * time.Minute,
+	}
+}
+
+// loadConfig reads key = value lines from path, a missing file keeps defaults
+func loadConfig(path string) (*config, error) {
+	cfg := defaultConfig()
+
+	file, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return cfg, nil
+		}
+		return cfg, fmt.Errorf("open config failed: %v", err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		parts := strings.SplitN(line, "=", 2)
+		if len(parts) != 2 {
+			return cfg, fmt.Errorf("config line %d: missing '='", lineNo)
+		}
+		key := strings.TrimSpace(parts[0])
+		value := strings.TrimSpace(parts[1])
+		if err := cfg.set(key, value); err != nil {
+			return cfg, fmt.Errorf("config line %d: %v", lineNo, err)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return cfg, fmt.Errorf("read config failed: %v", err)
+	}
+	return cfg, nil
+}
+
+// set applies a single config key
+func (cfg *config) set(key, value string) error {
+	var err error
+	switch key {
+	case "i2c_bus":
+		cfg.oled.Bus = value
+	case "i2c_address":
+		var addr int
+		if addr, err = parseInt(value); err == nil {
+			cfg.oled.Address = uint16(addr)
+		}
+	case "controller":
+		cfg.oled.Controller, err = nanohatoled.ParseController(value)
+	case "width":
+		cfg.oled.Width, err = parseInt(value)
+	case "height":
+		cfg.oled.Height, err = parseInt(value)
+	case "rotation":
+		if cfg.rotation, err = parseInt(value); err == nil && cfg.rotation%90 != 0 {
+			err = fmt.Errorf("not a multiple of 90")
+		}
+		cfg.rotation = (cfg.rotation%360 + 360) % 360
+	case "invert":
+		cfg.invert, err = strconv.ParseBool(value)
+	case "logo_dither", "logo_threshold", "logo_invert", "logo_gamma":
+		err = cfg.setLogo(key, value)
+	case "font_dir":
+		cfg.oled.FontDir = value
+	case "fallback_font_dir":
+		cfg.oled.FallbackFontDir = value
+	case "info_font":
+		cfg.infoFont = value
+	case "info_icons":
+		cfg.infoIcons, err = strconv.ParseBool(value)
+	case "icon_dir":
+		cfg.oled.IconDir = value
+	case "button_pins":
+		cfg.oled.ButtonPins = strings.Fields(strings.ReplaceAll(value, ",", " "))
+	case "button_active_low":
+		cfg.oled.ButtonActiveLow, err = strconv.ParseBool(value)
+	case "i2c_retries":
+		cfg.oled.Retries, err = parseInt(value)
+	case "i2c_retry_delay":
+		cfg.oled.RetryDelay, err = time.ParseDuration(value)
+	case "i2c_reinit_after":
+		cfg.oled.ReinitAfter, err = parseInt(value)
+	case "i2c_reinit_max_delay":
+		cfg.oled.ReinitMaxDelay, err = time.ParseDuration(value)
+	case "button_chip":
+		cfg.oled.ButtonChip = value
+	case "button_lines":
+		cfg.oled.ButtonLines = nil
+		for _, field := range strings.Fields(strings.ReplaceAll(value, ",", " ")) {
+			var n int
+			if n, err = parseInt(field); err != nil {
+				break
+			}
+			cfg.oled.ButtonLines = append(cfg.oled.ButtonLines, n)
+		}
+	case "button_bias":
+		cfg.oled.ButtonBias, err = nanohatoled.ParseBias(value)
+	case "buttons":
+		var enabled bool
+		if enabled, err = strconv.ParseBool(value); err == nil {
+			cfg.oled.NoButtons = !enabled
+		}
+	case "sleep_after":
+		cfg.sleepAfter, err = parseInt(value)
+	case "idle_dim":
+		cfg.idleDim, err = parseInt(value)
+	case "idle_contrast":
+		cfg.idleContrast, err = parseByte(value)
+	case "shift_pixels":
+		cfg.shiftPixels, err = parseInt(value)
+	case "shift_interval":
+		cfg.shiftInterval, err = time.ParseDuration(value)
+	case "button_source":
+		cfg.buttonSource, err = parseButtonSource(value)
+	case "poweroff":
+		cfg.poweroff, err = strconv.ParseBool(value)
+	case "contrast":
+		cfg.contrast, err = parseByte(value)
+	case "dim_contrast":
+		cfg.dimContrast, err = parseByte(value)
+	case "dim_start":
+		cfg.dimStart, err = parseClock(value)
+	case "dim_end":
+		cfg.dimEnd, err = parseClock(value)
+	case "precharge":
+		cfg.precharge, err = parseByte(value)
+	case "vcomh":
+		cfg.vcomh, err = parseByte(value)
+	default:
+		return fmt.Errorf("unknown key %q", key)
+	}
+	if err != nil {
+		return fmt.Errorf("invalid value for %s: %q", key, value)
+	}
+	return nil
+}
+
+// setLogo applies a logo_* key, enabling custom logo conversion
+func (cfg *config) setLogo(key, value string) error {
+	if cfg.logo == nil {
+		cfg.logo = &nanohatoled.ImageOptions{}
+	}
+	var err error
+	switch key {
+	case "logo_dither":
+		cfg.logo.Dither, err = nanohatoled.ParseDitherMode(value)
+	case "logo_threshold":
+		var n int
+		if n, err = parseByte(value); err == nil {
+			cfg.logo.Threshold = uint8(n)
+		}
+	case "logo_invert":
+		cfg.logo.Invert, err = strconv.ParseBool(value)
+	case "logo_gamma":
+		cfg.logo.Gamma, err = strconv.ParseFloat(value, 64)
+	}
+	return err
+}
+
+// parseButtonSource reads "gpio", "stdin", "fifo PATH", "socket PATH" or "script WORDS"
+func parseButtonSource(value string) (nanohatoled.ButtonSource, error) {
+	kind, arg, _ := strings.Cut(value, " ")
+	arg = strings.TrimSpace(arg)
+	if arg == "" && (kind == "fifo" || kind == "socket" || kind == "script") {
+		return nil, fmt.Errorf("%s needs an argument", kind)
+	}
+	switch kind {
+	case "gpio":
+		return nil, nil
+	case "stdin":
+		return nanohatoled.ReaderSource(os.Stdin), nil
+	case "fifo":
+		return nanohatoled.FIFOSource(arg), nil
+	case "socket":
+		return nanohatoled.SocketSource(arg), nil
+	case "script":
+		return nanohatoled.ScriptSource(arg)
+	}
+	return nil, fmt.Errorf("unknown button source %q", kind)
+}
+
+// parseInt accepts decimal or 0x-prefixed hex values
+func parseInt(value string) (int, error) {
+	n, err := strconv.ParseInt(value, 0, 32)
+	return int(n), err
+}
+
+// parseByte accepts a 0-255 value in decimal or hex
+func parseByte(value string) (int, error) {
+	n, err := parseInt(value)
+	if err == nil && (n < 0 || n > 255) {
+		err = fmt.Errorf("out of range")
+	}
+	return n, err
+}
+
+// parseClock converts HH:MM into minutes after midnight
+func parseClock(value string) (int, error) {
+	t, err := time.Parse("15:04", value)
+	if err != nil {
+		return -1, err
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
@@ -0,0 +1,229 @@
+package nanohatoled
+
+import (
+	"context"
+	"fmt"
+	"image"
+	"image/draw"
+	"image/gif"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/disintegration/imaging"
+)
+
+const defaultFrameDelay = 100 * time.Millisecond // Used when a GIF frame has no delay
+
+// AnimationFrame - One frame already converted to 1 bit
+type AnimationFrame struct {
+	Image *Mono
+	Delay time.Duration
+}
+
+// Animation - Frame sequence ready for Play
+type Animation struct {
+	Frames []AnimationFrame
+	Loops  int // Number of times to play, 0 repeats until cancelled
+}
+
+// convertFrame - Scale a composited frame into a w x h box (native size when zero) and dither it
+func convertFrame(img image.Image, w, h int, opts ImageOptions) *Mono {
+	if w <= 0 || h <= 0 {
+		return Dither(img, opts)
+	}
+	scaled := scaleToBox(img, w, h, opts.Scale)
+	if opts.Scale == ScaleFit || opts.Scale == ScaleCenter {
+		// Center in the box so every frame has the same size
+		boxed := imaging.New(w, h, image.Black)
+		scaled = imaging.PasteCenter(boxed, scaled)
+	}
+	return Dither(scaled, opts)
+}
+
+// LoadGIF - Decode an animated GIF, composite its frames (honouring disposal) and convert
+// each to 1 bit sized for a w x h box
+func LoadGIF(r io.Reader, w, h int, opts ImageOptions) (*Animation, error) {
+	g, err := gif.DecodeAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("decode GIF failed: %w", err)
+	}
+	if len(g.Image) == 0 {
+		return nil, fmt.Errorf("GIF has no frames")
+	}
+
+	bounds := image.Rect(0, 0, g.Config.Width, g.Config.Height)
+	if bounds.Empty() {
+		bounds = g.Image[0].Bounds()
+	}
+	canvas := image.NewRGBA(bounds)
+	anim := &Animation{}
+	switch {
+	case g.LoopCount == 0:
+		anim.Loops = 0 // Forever
+	case g.LoopCount < 0:
+		anim.Loops = 1
+	default:
+		anim.Loops = g.LoopCount + 1
+	}
+
+	for i, frame := range g.Image {
+		var previous *image.RGBA
+		disposal := byte(0)
+		if i < len(g.Disposal) {
+			disposal = g.Disposal[i]
+		}
+		if disposal == gif.DisposalPrevious {
+			previous = image.NewRGBA(bounds)
+			copy(previous.Pix, canvas.Pix)
+		}
+
+		draw.Draw(canvas, frame.Bounds(), frame, frame.Bounds().Min, draw.Over)
+
+		delay := defaultFrameDelay
+		if i < len(g.Delay) && g.Delay[i] > 0 {
+			delay = time.Duration(g.Delay[i]) * 10 * time.Millisecond
+		}
+		anim.Frames = append(anim.Frames, AnimationFrame{
+			Image: convertFrame(canvas, w, h, opts),
+			Delay: delay,
+		})
+
+		switch disposal {
+		case gif.DisposalBackground:
+			draw.Draw(canvas, frame.Bounds(), image.Transparent, image.Point{}, draw.Src)
+		case gif.DisposalPrevious:
+			copy(canvas.Pix, previous.Pix)
+		}
+	}
+	return anim, nil
+}
+
+// LoadGIFFile - LoadGIF from a file path
+func LoadGIFFile(path string, w, h int, opts ImageOptions) (*Animation, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("open GIF failed: %w", err)
+	}
+	defer f.Close()
+	return LoadGIF(f, w, h, opts)
+}
+
+// frameNumber - Last run of digits in a file name, used to order frame sequences
+var frameNumber = regexp.MustCompile(`(\d+)\D*$`)
+
+// LoadFrames - Load a numbered image sequence matching pattern (e.g. "/etc/NanoHatOLED/boot/*.png"),
+// ordered by the number in each file name, every frame shown for delay
+func LoadFrames(pattern string, delay time.Duration, w, h int, opts ImageOptions) (*Animation, error) {
+	paths, err := filepath.Glob(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("bad frame pattern: %w", err)
+	}
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("no frames match %s", pattern)
+	}
+
+	number := func(path string) int {
+		m := frameNumber.FindStringSubmatch(filepath.Base(path))
+		if m == nil {
+			return -1
+		}
+		n, _ := strconv.Atoi(m[1])
+		return n
+	}
+	sort.SliceStable(paths, func(i, j int) bool { return number(paths[i]) < number(paths[j]) })
+
+	if delay <= 0 {
+		delay = defaultFrameDelay
+	}
+	anim := &Animation{Loops: 1}
+	for _, path := range paths {
+		img, err := imaging.Open(path)
+		if err != nil {
+			return nil, fmt.Errorf("open frame failed: %w", err)
+		}
+		anim.Frames = append(anim.Frames, AnimationFrame{
+			Image: convertFrame(img, w, h, opts),
+			Delay: delay,
+		})
+	}
+	return anim, nil
+}
+
+// Spinner - Built-in busy indicator: a ring of dots with a fading tail, size x size pixels
+func Spinner(size int, delay time.Duration) *Animation {
+	const dots = 8
+	if delay <= 0 {
+		delay = defaultFrameDelay
+	}
+	radius := float64(size)/2 - 2
+	center := float64(size-1) / 2
+
+	anim := &Animation{}
+	for i := 0; i < dots; i++ {
+		m := NewMono(size, size)
+		// Head dot is 3x3, the two dots behind it shrink to 2x2 and 1x1
+		for tail := 0; tail < 3; tail++ {
+			angle := 2 * math.Pi * float64(i-tail) / dots
+			cx := int(math.Round(center + radius*math.Sin(angle)))
+			cy := int(math.Round(center - radius*math.Cos(angle)))
+			dotSize := 3 - tail
+			for dy := 0; dy < dotSize; dy++ {
+				for dx := 0; dx < dotSize; dx++ {
+					m.SetBit(cx-dotSize/2+dx, cy-dotSize/2+dy, true)
+				}
+			}
+		}
+		anim.Frames = append(anim.Frames, AnimationFrame{Image: m, Delay: delay})
+	}
+	return anim
+}
+
+// Play - Show anim with its top-left corner at (x, y), sending every frame and honouring
+// frame delays. Returns nil after the last loop, or ctx.Err() when cancelled.
+func (nanoOled *NanoOled) Play(ctx context.Context, anim *Animation, x int, y int) error {
+	if anim == nil || len(anim.Frames) == 0 {
+		return fmt.Errorf("animation has no frames")
+	}
+
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+	<-timer.C
+
+	next := time.Now()
+	for loop := 0; anim.Loops == 0 || loop < anim.Loops; loop++ {
+		for _, frame := range anim.Frames {
+			b := frame.Image.Rect
+			for py := 0; py < b.Dy(); py++ {
+				for px := 0; px < b.Dx(); px++ {
+					nanoOled.set(x+px, y+py, frame.Image.Bit(b.Min.X+px, b.Min.Y+py))
+				}
+			}
+			if err := nanoOled.Send(); err != nil {
+				return err
+			}
+
+			// Schedule against the previous deadline so drawing time does not add up
+			next = next.Add(frame.Delay)
+			if wait := time.Until(next); wait > 0 {
+				timer.Reset(wait)
+				select {
+				case <-ctx.Done():
+					return ctx.Err()
+				case <-timer.C:
+				}
+			} else {
+				next = time.Now()
+				if err := ctx.Err(); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
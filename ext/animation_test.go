@@ -0,0 +1,126 @@
+package nanohatoled
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"image/color"
+	"image/gif"
+	"testing"
+	"time"
+)
+
+// testGIF - 4x1 GIF whose frame i lights pixel i only, each frame covering just that pixel
+// (the first covers the whole image), with the given disposal per frame
+func testGIF(t *testing.T, loopCount int, disposal ...byte) []byte {
+	t.Helper()
+	palette := color.Palette{color.Black, color.White}
+	g := &gif.GIF{LoopCount: loopCount, Config: image.Config{Width: 4, Height: 1, ColorModel: palette}}
+	for i := range disposal {
+		r := image.Rect(i, 0, i+1, 1)
+		if i == 0 {
+			r = image.Rect(0, 0, 4, 1)
+		}
+		frame := image.NewPaletted(r, palette)
+		frame.SetColorIndex(i, 0, 1)
+		g.Image = append(g.Image, frame)
+		g.Delay = append(g.Delay, 1)
+		g.Disposal = append(g.Disposal, disposal[i])
+	}
+	var buf bytes.Buffer
+	if err := gif.EncodeAll(&buf, g); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestLoadGIFDisposal(t *testing.T) {
+	cases := []struct {
+		name     string
+		disposal []byte
+		want     []string
+	}{
+		{"none", []byte{gif.DisposalNone, gif.DisposalNone, gif.DisposalNone, gif.DisposalNone},
+			[]string{"#...", "##..", "###.", "####"}},
+		{"background", []byte{gif.DisposalBackground, gif.DisposalNone, gif.DisposalBackground, gif.DisposalNone},
+			[]string{"#...", ".#..", ".##.", ".#.#"}},
+		{"previous", []byte{gif.DisposalNone, gif.DisposalPrevious, gif.DisposalPrevious, gif.DisposalNone},
+			[]string{"#...", "##..", "#.#.", "#..#"}},
+	}
+	for _, c := range cases {
+		anim, err := LoadGIF(bytes.NewReader(testGIF(t, 0, c.disposal...)), 0, 0, ImageOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(anim.Frames) != len(c.want) {
+			t.Fatalf("%s: %d frames", c.name, len(anim.Frames))
+		}
+		for i, frame := range anim.Frames {
+			if got := monoRows(frame.Image)[0]; got != c.want[i] {
+				t.Errorf("%s: frame %d is %s, want %s", c.name, i, got, c.want[i])
+			}
+			if frame.Delay != 10*time.Millisecond {
+				t.Errorf("%s: frame %d delay %v", c.name, i, frame.Delay)
+			}
+		}
+	}
+}
+
+func TestLoadGIFLoops(t *testing.T) {
+	// GIF loop count is the number of repeats after the first play, -1 plays once.
+	// Two frames, image/gif drops the loop count of a still image.
+	for loopCount, want := range map[int]int{0: 0, -1: 1, 1: 2, 4: 5} {
+		gifData := testGIF(t, loopCount, gif.DisposalNone, gif.DisposalNone)
+		anim, err := LoadGIF(bytes.NewReader(gifData), 0, 0, ImageOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if anim.Loops != want {
+			t.Errorf("loop count %d: %d loops, want %d", loopCount, anim.Loops, want)
+		}
+	}
+}
+
+// blinkAnimation - A lit and a dark 1x1 frame, so every frame sends one changed byte
+func blinkAnimation(loops int, delay time.Duration) *Animation {
+	lit := NewMono(1, 1)
+	lit.SetBit(0, 0, true)
+	return &Animation{Frames: []AnimationFrame{{lit, delay}, {NewMono(1, 1), delay}}, Loops: loops}
+}
+
+func TestPlayLoops(t *testing.T) {
+	oled, rec := testOled(t, SSD1306)
+	if err := oled.Play(context.Background(), blinkAnimation(2, time.Millisecond), 0, 0); err != nil {
+		t.Fatal(err)
+	}
+	// Two loops of two frames, a window command and the data byte each
+	if len(rec.writes) != 8 {
+		t.Errorf("%d writes, want 8", len(rec.writes))
+	}
+}
+
+func TestPlayStops(t *testing.T) {
+	oled, rec := testOled(t, SSD1306)
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	err := oled.Play(ctx, blinkAnimation(0, time.Hour), 0, 0)
+	if err != context.DeadlineExceeded || time.Since(start) > time.Second {
+		t.Fatalf("Play returned %v after %v", err, time.Since(start))
+	}
+	// Only the first frame went out
+	if len(rec.writes) != 2 || !oled.image.Bit(0, 0) {
+		t.Errorf("%d writes, pixel lit %v", len(rec.writes), oled.image.Bit(0, 0))
+	}
+
+	// Forever keeps going past the first loop until cancelled
+	ctx, cancel = context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	rec.writes = nil
+	if err := oled.Play(ctx, blinkAnimation(0, time.Millisecond), 0, 0); err != context.DeadlineExceeded {
+		t.Fatal(err)
+	}
+	if len(rec.writes) <= 4 {
+		t.Errorf("%d writes, want more than one loop", len(rec.writes))
+	}
+}
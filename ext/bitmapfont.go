@@ -0,0 +1,522 @@
+package nanohatoled
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"image"
+	"io"
+	"strconv"
+	"strings"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/math/fixed"
+)
+
+// BitmapFont - Fixed-size pixel font loaded from BDF or PCF. Encodings are taken as
+// Unicode code points, which holds for ISO10646 and ISO8859-1 fonts.
+type BitmapFont struct {
+	Name        string
+	glyphs      map[rune]*bitmapGlyph
+	ascent      int
+	descent     int
+	defaultRune rune // Drawn for missing runes, -1 if the font has none
+}
+
+// bitmapGlyph - One glyph; x, y place the mask's top-left relative to the dot
+type bitmapGlyph struct {
+	mask    *image.Alpha
+	x, y    int
+	advance int
+	bold    *image.Alpha // Overstruck mask, built on first use
+}
+
+// Height - Line height in pixels
+func (f *BitmapFont) Height() int {
+	return f.ascent + f.descent
+}
+
+// HasGlyph - Whether the font has its own glyph for r
+func (f *BitmapFont) HasGlyph(r rune) bool {
+	_, ok := f.glyphs[r]
+	return ok
+}
+
+func (f *BitmapFont) face(size float64, bold bool) font.Face {
+	return &bitmapFace{f: f, bold: bold}
+}
+
+func (f *BitmapFont) hasGlyph(r rune) bool {
+	return f.HasGlyph(r)
+}
+
+func (f *BitmapFont) glyph(r rune) (*bitmapGlyph, bool) {
+	if g, ok := f.glyphs[r]; ok {
+		return g, true
+	}
+	g, ok := f.glyphs[f.defaultRune]
+	return g, ok
+}
+
+// boldMask - Mask widened by one pixel to the right
+func (g *bitmapGlyph) boldMask() *image.Alpha {
+	if g.bold != nil {
+		return g.bold
+	}
+	w, h := g.mask.Rect.Dx(), g.mask.Rect.Dy()
+	if w == 0 {
+		g.bold = g.mask
+		return g.bold
+	}
+	b := image.NewAlpha(image.Rect(0, 0, w+1, h))
+	for y := 0; y < h; y++ {
+		src := g.mask.Pix[y*g.mask.Stride : y*g.mask.Stride+w]
+		dst := b.Pix[y*b.Stride : y*b.Stride+w+1]
+		for x, a := range src {
+			if a != 0 {
+				dst[x], dst[x+1] = 0xff, 0xff
+			}
+		}
+	}
+	g.bold = b
+	return b
+}
+
+// bitmapFace - font.Face over a BitmapFont
+type bitmapFace struct {
+	f    *BitmapFont
+	bold bool
+}
+
+func (face *bitmapFace) Close() error { return nil }
+
+func (face *bitmapFace) Glyph(dot fixed.Point26_6, r rune) (
+	dr image.Rectangle, mask image.Image, maskp image.Point, advance fixed.Int26_6, ok bool) {
+	g, ok := face.f.glyph(r)
+	if !ok {
+		return
+	}
+	m := g.mask
+	if face.bold {
+		m = g.boldMask()
+	}
+	x, y := dot.X.Floor()+g.x, dot.Y.Floor()+g.y
+	dr = image.Rect(x, y, x+m.Rect.Dx(), y+m.Rect.Dy())
+	return dr, m, image.Point{}, fixed.I(g.advance), true
+}
+
+func (face *bitmapFace) GlyphBounds(r rune) (bounds fixed.Rectangle26_6, advance fixed.Int26_6, ok bool) {
+	g, ok := face.f.glyph(r)
+	if !ok {
+		return
+	}
+	w, h := g.mask.Rect.Dx(), g.mask.Rect.Dy()
+	if face.bold && w > 0 {
+		w++
+	}
+	bounds = fixed.Rectangle26_6{Min: fixed.P(g.x, g.y), Max: fixed.P(g.x+w, g.y+h)}
+	return bounds, fixed.I(g.advance), true
+}
+
+func (face *bitmapFace) GlyphAdvance(r rune) (advance fixed.Int26_6, ok bool) {
+	g, ok := face.f.glyph(r)
+	if !ok {
+		return
+	}
+	return fixed.I(g.advance), true
+}
+
+func (face *bitmapFace) Kern(r0, r1 rune) fixed.Int26_6 { return 0 }
+
+func (face *bitmapFace) Metrics() font.Metrics {
+	return font.Metrics{
+		Height:     fixed.I(face.f.Height()),
+		Ascent:     fixed.I(face.f.ascent),
+		Descent:    fixed.I(face.f.descent),
+		CaretSlope: image.Point{X: 0, Y: 1},
+	}
+}
+
+// Limits for BDF fonts, which unlike PCF have no file size to check counts against.
+// Unifont (every BMP rune at 16 pixels) stays below them.
+const (
+	maxBDFGlyphSize = 256     // Largest BBX width or height, far above anything a 128x64 panel can show
+	maxBDFGlyphs    = 65536   // Glyphs per font
+	maxBDFPixels    = 1 << 24 // Bitmap pixels of all glyphs together, one byte each in memory
+)
+
+// ParseBDF - Parse a font in Glyph Bitmap Distribution Format
+func ParseBDF(r io.Reader) (*BitmapFont, error) {
+	f := &BitmapFont{glyphs: map[rune]*bitmapGlyph{}, defaultRune: -1}
+	var (
+		g          *bitmapGlyph
+		code       = -1
+		row        = -1 // Next BITMAP row, -1 outside a bitmap
+		dwidth     int  // Font-wide DWIDTH
+		boxAscent  int
+		boxDescent int
+		glyphCount int
+		pixels     int // Allocated by BBX so far
+	)
+
+	sc := bufio.NewScanner(r)
+	for line := 1; sc.Scan(); line++ {
+		fields := strings.Fields(sc.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		fail := func(msg string) error {
+			return fmt.Errorf("bdf: line %d: %s", line, msg)
+		}
+
+		if row >= 0 {
+			if fields[0] == "ENDCHAR" {
+				if code >= 0 {
+					f.glyphs[rune(code)] = g
+				}
+				g, row = nil, -1
+				continue
+			}
+			if row >= g.mask.Rect.Dy() {
+				return nil, fail("too many bitmap rows")
+			}
+			bits, err := hex.DecodeString(fields[0])
+			if err != nil {
+				return nil, fail("bad bitmap row")
+			}
+			for x := 0; x < g.mask.Rect.Dx() && x/8 < len(bits); x++ {
+				if bits[x/8]&(0x80>>(x%8)) != 0 {
+					g.mask.Pix[row*g.mask.Stride+x] = 0xff
+				}
+			}
+			row++
+			continue
+		}
+
+		switch fields[0] {
+		case "FONT":
+			f.Name = strings.Join(fields[1:], " ")
+		case "FONTBOUNDINGBOX":
+			n, err := bdfInts(fields, 4)
+			if err != nil {
+				return nil, fail(err.Error())
+			}
+			boxAscent, boxDescent = n[1]+n[3], -n[3]
+		case "FONT_ASCENT", "FONT_DESCENT", "DEFAULT_CHAR":
+			n, err := bdfInts(fields, 1)
+			if err != nil {
+				return nil, fail(err.Error())
+			}
+			switch fields[0] {
+			case "FONT_ASCENT":
+				f.ascent = n[0]
+			case "FONT_DESCENT":
+				f.descent = n[0]
+			default:
+				f.defaultRune = rune(n[0])
+			}
+		case "STARTCHAR":
+			if glyphCount++; glyphCount > maxBDFGlyphs {
+				return nil, fail("too many glyphs")
+			}
+			g, code = &bitmapGlyph{advance: dwidth, mask: &image.Alpha{}}, -1
+		case "ENCODING":
+			n, err := bdfInts(fields, 1)
+			if err != nil {
+				return nil, fail(err.Error())
+			}
+			code = n[0] // -1 marks glyphs without a standard encoding
+		case "DWIDTH":
+			n, err := bdfInts(fields, 1)
+			if err != nil {
+				return nil, fail(err.Error())
+			}
+			if g == nil {
+				dwidth = n[0]
+			} else {
+				g.advance = n[0]
+			}
+		case "BBX":
+			if g == nil {
+				return nil, fail("BBX outside STARTCHAR")
+			}
+			n, err := bdfInts(fields, 4)
+			if err != nil {
+				return nil, fail(err.Error())
+			}
+			if n[0] < 0 || n[1] < 0 {
+				return nil, fail("negative BBX size")
+			}
+			if n[0] > maxBDFGlyphSize || n[1] > maxBDFGlyphSize {
+				return nil, fail("BBX size too large")
+			}
+			if pixels += n[0] * n[1]; pixels > maxBDFPixels {
+				return nil, fail("glyph bitmaps too large")
+			}
+			g.mask = image.NewAlpha(image.Rect(0, 0, n[0], n[1]))
+			g.x, g.y = n[2], -(n[3] + n[1])
+		case "BITMAP":
+			if g == nil {
+				return nil, fail("BITMAP outside STARTCHAR")
+			}
+			row = 0
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(f.glyphs) == 0 {
+		return nil, errors.New("bdf: no glyphs")
+	}
+	if f.ascent == 0 && f.descent == 0 {
+		f.ascent, f.descent = boxAscent, boxDescent
+	}
+	if f.ascent == 0 && f.descent == 0 {
+		// No font-wide metrics at all, span the glyph boxes
+		for _, g := range f.glyphs {
+			if -g.y > f.ascent {
+				f.ascent = -g.y
+			}
+			if d := g.y + g.mask.Rect.Dy(); d > f.descent {
+				f.descent = d
+			}
+		}
+	}
+	return f, nil
+}
+
+// bdfInts - The n integer arguments of a BDF statement
+func bdfInts(fields []string, n int) ([]int, error) {
+	if len(fields) < n+1 {
+		return nil, fmt.Errorf("%s needs %d values", fields[0], n)
+	}
+	v := make([]int, n)
+	for i := range v {
+		var err error
+		if v[i], err = strconv.Atoi(fields[i+1]); err != nil {
+			return nil, fmt.Errorf("%s: %w", fields[0], err)
+		}
+	}
+	return v, nil
+}
+
+// PCF (Portable Compiled Format) layout as written by bdftopcf
+const (
+	pcfMagic = "\x01fcp"
+
+	pcfAccelerators    = 1 << 1
+	pcfMetrics         = 1 << 2
+	pcfBitmaps         = 1 << 3
+	pcfBDFEncodings    = 1 << 5
+	pcfBDFAccelerators = 1 << 8
+
+	pcfFormatMask        = 0xffffff00
+	pcfCompressedMetrics = 0x100
+	pcfByteMSB           = 1 << 2
+	pcfBitMSB            = 1 << 3
+)
+
+var errPCFShort = errors.New("pcf: truncated table")
+
+// pcfReader - Reads one table in its own byte order
+type pcfReader struct {
+	b      []byte
+	off    int
+	format uint32
+	order  binary.ByteOrder
+	err    error
+}
+
+// newPCFReader - Reader past the table's format word (always little-endian)
+func newPCFReader(b []byte) *pcfReader {
+	r := &pcfReader{b: b, off: 4, order: binary.LittleEndian}
+	if len(b) < 4 {
+		r.err = errPCFShort
+		return r
+	}
+	r.format = binary.LittleEndian.Uint32(b)
+	if r.format&pcfByteMSB != 0 {
+		r.order = binary.BigEndian
+	}
+	return r
+}
+
+func (r *pcfReader) next(n int) []byte {
+	if r.err != nil || n < 0 || n > len(r.b)-r.off {
+		r.err = errPCFShort
+		return make([]byte, 4) // Enough for the number readers, callers check err
+	}
+	p := r.b[r.off : r.off+n]
+	r.off += n
+	return p
+}
+
+func (r *pcfReader) u8() int    { return int(r.next(1)[0]) }
+func (r *pcfReader) u16() int   { return int(r.order.Uint16(r.next(2))) }
+func (r *pcfReader) i16() int   { return int(int16(r.order.Uint16(r.next(2)))) }
+func (r *pcfReader) i32() int   { return int(int32(r.order.Uint32(r.next(4)))) }
+func (r *pcfReader) skip(n int) { r.next(n) }
+
+// count - Check a count read from the table: n entries of size bytes must fit in the rest of it
+func (r *pcfReader) count(n, size int) int {
+	if r.err == nil && (n < 0 || n > (len(r.b)-r.off)/size) {
+		r.err = errPCFShort
+	}
+	if r.err != nil {
+		return 0
+	}
+	return n
+}
+
+// pcfMetric - Glyph metrics as stored in the METRICS table
+type pcfMetric struct {
+	lsb, rsb, width, ascent, descent int
+}
+
+// ParsePCF - Parse a font in X11 Portable Compiled Format
+func ParsePCF(data []byte) (*BitmapFont, error) {
+	if len(data) < 8 || string(data[:4]) != pcfMagic {
+		return nil, errors.New("pcf: bad magic")
+	}
+	count := int(binary.LittleEndian.Uint32(data[4:]))
+	if count > (len(data)-8)/16 {
+		return nil, errPCFShort
+	}
+	tables := map[uint32][]byte{}
+	for i := 0; i < count; i++ {
+		entry := data[8+16*i:]
+		typ := binary.LittleEndian.Uint32(entry)
+		size := binary.LittleEndian.Uint32(entry[8:])
+		offset := binary.LittleEndian.Uint32(entry[12:])
+		if uint64(offset)+uint64(size) > uint64(len(data)) {
+			return nil, errPCFShort
+		}
+		tables[typ] = data[offset : offset+size]
+	}
+	for _, typ := range []uint32{pcfMetrics, pcfBitmaps, pcfBDFEncodings} {
+		if tables[typ] == nil {
+			return nil, fmt.Errorf("pcf: missing table %#x", typ)
+		}
+	}
+
+	// Glyph metrics
+	r := newPCFReader(tables[pcfMetrics])
+	var metrics []pcfMetric
+	if r.format&pcfFormatMask == pcfCompressedMetrics {
+		metrics = make([]pcfMetric, r.count(r.i16(), 5))
+		for i := range metrics {
+			metrics[i] = pcfMetric{r.u8() - 0x80, r.u8() - 0x80, r.u8() - 0x80, r.u8() - 0x80, r.u8() - 0x80}
+		}
+	} else {
+		metrics = make([]pcfMetric, r.count(r.i32(), 12))
+		for i := range metrics {
+			metrics[i] = pcfMetric{r.i16(), r.i16(), r.i16(), r.i16(), r.i16()}
+			r.skip(2) // Attributes
+		}
+	}
+	if r.err != nil {
+		return nil, r.err
+	}
+
+	// Glyph bitmaps
+	r = newPCFReader(tables[pcfBitmaps])
+	offsets := make([]int, r.count(r.i32(), 4))
+	if r.err != nil {
+		return nil, r.err
+	}
+	if len(offsets) != len(metrics) {
+		return nil, errors.New("pcf: bitmap and metric counts differ")
+	}
+	for i := range offsets {
+		offsets[i] = r.i32()
+	}
+	var sizes [4]int
+	for i := range sizes {
+		sizes[i] = r.i32()
+	}
+	bitmaps := r.next(sizes[r.format&3])
+	if r.err != nil {
+		return nil, r.err
+	}
+	pad := 1 << (r.format & 3)
+	unit := 1 << ((r.format >> 4) & 3)
+	bitMSB := r.format&pcfBitMSB != 0
+	swap := unit > 1 && (r.format&pcfByteMSB != 0) != bitMSB
+
+	glyphs := make([]*bitmapGlyph, len(metrics))
+	for i, m := range metrics {
+		w, h := m.rsb-m.lsb, m.ascent+m.descent
+		if w < 0 || h < 0 {
+			return nil, errors.New("pcf: negative glyph size")
+		}
+		stride := (w + 8*pad - 1) / (8 * pad) * pad
+		if offsets[i] < 0 || offsets[i]+stride*h > len(bitmaps) {
+			return nil, errPCFShort
+		}
+		g := &bitmapGlyph{mask: image.NewAlpha(image.Rect(0, 0, w, h)), x: m.lsb, y: -m.ascent, advance: m.width}
+		src := bitmaps[offsets[i]:]
+		for y := 0; y < h; y++ {
+			for x := 0; x < w; x++ {
+				i := x / 8
+				if swap {
+					i += unit - 1 - 2*(i%unit)
+				}
+				bit := byte(1) << (x % 8)
+				if bitMSB {
+					bit = 0x80 >> (x % 8)
+				}
+				if src[y*stride+i]&bit != 0 {
+					g.mask.Pix[y*g.mask.Stride+x] = 0xff
+				}
+			}
+		}
+		glyphs[i] = g
+	}
+
+	// Encoding to glyph index
+	f := &BitmapFont{glyphs: map[rune]*bitmapGlyph{}, defaultRune: -1}
+	r = newPCFReader(tables[pcfBDFEncodings])
+	min2, max2, min1, max1 := r.i16(), r.i16(), r.i16(), r.i16()
+	defaultChar := r.i16()
+	for b1 := min1; b1 <= max1; b1++ {
+		for b2 := min2; b2 <= max2; b2++ {
+			idx := r.u16()
+			if r.err != nil {
+				return nil, r.err
+			}
+			if idx != 0xffff && idx < len(glyphs) {
+				f.glyphs[rune(b1<<8|b2)] = glyphs[idx]
+			}
+		}
+	}
+	if _, ok := f.glyphs[rune(defaultChar)]; ok {
+		f.defaultRune = rune(defaultChar)
+	}
+
+	// Font ascent and descent, preferring the BDF accelerators
+	accel := tables[pcfBDFAccelerators]
+	if accel == nil {
+		accel = tables[pcfAccelerators]
+	}
+	if accel != nil {
+		r = newPCFReader(accel)
+		r.skip(8) // Flags and padding
+		f.ascent, f.descent = r.i32(), r.i32()
+		if r.err != nil {
+			return nil, r.err
+		}
+	} else {
+		for _, m := range metrics {
+			if m.ascent > f.ascent {
+				f.ascent = m.ascent
+			}
+			if m.descent > f.descent {
+				f.descent = m.descent
+			}
+		}
+	}
+	return f, nil
+}
@@ -0,0 +1,143 @@
+package nanohatoled
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// pcfFile - PCF font holding the given tables, values little-endian
+func pcfFile(tables map[uint32][]interface{}) []byte {
+	types := []uint32{pcfMetrics, pcfBitmaps, pcfBDFEncodings}
+	bodies := make([][]byte, len(types))
+	for i, typ := range types {
+		var b bytes.Buffer
+		for _, v := range tables[typ] {
+			binary.Write(&b, binary.LittleEndian, v)
+		}
+		bodies[i] = b.Bytes()
+	}
+
+	var out bytes.Buffer
+	out.WriteString(pcfMagic)
+	binary.Write(&out, binary.LittleEndian, uint32(len(types)))
+	offset := 8 + 16*len(types)
+	for i, typ := range types {
+		binary.Write(&out, binary.LittleEndian, []uint32{typ, 0, uint32(len(bodies[i])), uint32(offset)})
+		offset += len(bodies[i])
+	}
+	for _, body := range bodies {
+		out.Write(body)
+	}
+	return out.Bytes()
+}
+
+// pcfTables - One glyph for 'A', a single lit pixel
+func pcfTables() map[uint32][]interface{} {
+	return map[uint32][]interface{}{
+		pcfMetrics: {uint32(0), int32(1),
+			int16(0), int16(1), int16(1), int16(1), int16(0), int16(0)},
+		pcfBitmaps: {uint32(0), int32(1),
+			int32(0),
+			int32(1), int32(1), int32(1), int32(1),
+			uint8(0x01)},
+		pcfBDFEncodings: {uint32(0),
+			int16(0x41), int16(0x41), int16(0), int16(0), int16(0x41),
+			uint16(0)},
+	}
+}
+
+func TestParsePCF(t *testing.T) {
+	f, err := ParsePCF(pcfFile(pcfTables()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !f.HasGlyph('A') || f.HasGlyph('B') {
+		t.Errorf("glyphs %v", f.glyphs)
+	}
+}
+
+func TestParsePCFCorrupt(t *testing.T) {
+	cases := []struct {
+		name  string
+		table uint32
+		index int
+		value interface{}
+	}{
+		{"negative metric count", pcfMetrics, 1, int32(-1)},
+		{"huge metric count", pcfMetrics, 1, int32(0x7fffffff)},
+		{"negative bitmap count", pcfBitmaps, 1, int32(-1)},
+		{"huge bitmap count", pcfBitmaps, 1, int32(0x7fffffff)},
+		{"negative bitmap size", pcfBitmaps, 3, int32(-8)},
+		{"huge bitmap size", pcfBitmaps, 3, int32(0x7fffffff)},
+		{"negative bitmap offset", pcfBitmaps, 2, int32(-1)},
+		{"huge glyph", pcfMetrics, 3, int16(0x7fff)},
+	}
+	for _, c := range cases {
+		tables := pcfTables()
+		tables[c.table][c.index] = c.value
+		if _, err := ParsePCF(pcfFile(tables)); err == nil {
+			t.Errorf("%s: no error", c.name)
+		}
+	}
+}
+
+// bdfFile - BDF font with a single glyph for 'A' of the given BBX
+func bdfFile(bbx string) string {
+	return "STARTFONT 2.1\nFONT test\nFONTBOUNDINGBOX 1 1 0 0\nCHARS 1\n" +
+		"STARTCHAR A\nENCODING 65\nDWIDTH 2 0\nBBX " + bbx + "\nBITMAP\n80\nENDCHAR\nENDFONT\n"
+}
+
+func TestParseBDFBBX(t *testing.T) {
+	if _, err := ParseBDF(strings.NewReader(bdfFile("1 1 0 0"))); err != nil {
+		t.Fatal(err)
+	}
+	for _, bbx := range []string{"-1 1 0 0", "1 -1 0 0", "3000000000 3000000000 0 0", "257 1 0 0", "1 257 0 0"} {
+		if _, err := ParseBDF(strings.NewReader(bdfFile(bbx))); err == nil {
+			t.Errorf("BBX %s: no error", bbx)
+		}
+	}
+}
+
+func TestParseBDFHeightFromGlyphs(t *testing.T) {
+	src := "STARTFONT 2.1\nCHARS 2\n" +
+		"STARTCHAR A\nENCODING 65\nDWIDTH 4 0\nBBX 3 5 0 0\nBITMAP\n40\nA0\nE0\nA0\nA0\nENDCHAR\n" +
+		"STARTCHAR g\nENCODING 103\nDWIDTH 4 0\nBBX 3 4 0 -2\nBITMAP\n60\nA0\n60\nC0\nENDCHAR\nENDFONT\n"
+	f, err := ParseBDF(strings.NewReader(src))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f.ascent != 5 || f.descent != 2 {
+		t.Errorf("ascent %d descent %d, want 5 and 2", f.ascent, f.descent)
+	}
+}
+
+func TestParseBDFLimits(t *testing.T) {
+	font := func(glyphs int, bbx string) string {
+		var b strings.Builder
+		b.WriteString("STARTFONT 2.1\nFONTBOUNDINGBOX 1 1 0 0\n")
+		for i := 0; i < glyphs; i++ {
+			fmt.Fprintf(&b, "STARTCHAR g\nENCODING %d\nBBX %s\nBITMAP\nENDCHAR\n", i, bbx)
+		}
+		b.WriteString("ENDFONT\n")
+		return b.String()
+	}
+	cases := []struct {
+		name   string
+		src    string
+		wantOK bool
+	}{
+		{"glyph limit", font(maxBDFGlyphs, "0 0 0 0"), true},
+		{"too many glyphs", font(maxBDFGlyphs+1, "0 0 0 0"), false},
+		{"pixel limit", font(maxBDFPixels/(256*256), "256 256 0 0"), true},
+		{"too many pixels", font(maxBDFPixels/(256*256)+1, "256 256 0 0"), false},
+	}
+	for _, c := range cases {
+		_, err := ParseBDF(strings.NewReader(c.src))
+		if (err == nil) != c.wantOK {
+			t.Errorf("%s: error %v", c.name, err)
+		}
+	}
+}
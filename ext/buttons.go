@@ -0,0 +1,326 @@
+package nanohatoled
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"periph.io/x/periph/conn/gpio"
+)
+
+// ButtonEventType - Kind of ButtonEvent
+type ButtonEventType int
+
+const (
+	ButtonPress       ButtonEventType = iota // Button went down
+	ButtonRelease                            // Button came up, Hold is how long it was down
+	ButtonClick                              // Short press and release, not part of a double click or chord
+	ButtonLongPress                          // Button held for LongPress
+	ButtonRepeat                             // Button still held, every RepeatInterval after the long press
+	ButtonDoubleClick                        // Second click within DoubleClick of the first
+	ButtonChord                              // Several buttons down together, Mask lists them
+)
+
+// String - Event type name
+func (t ButtonEventType) String() string {
+	switch t {
+	case ButtonPress:
+		return "press"
+	case ButtonRelease:
+		return "release"
+	case ButtonClick:
+		return "click"
+	case ButtonLongPress:
+		return "long-press"
+	case ButtonRepeat:
+		return "repeat"
+	case ButtonDoubleClick:
+		return "double-click"
+	case ButtonChord:
+		return "chord"
+	}
+	return fmt.Sprintf("ButtonEventType(%d)", int(t))
+}
+
+// ButtonEvent - Something a button or button combination did
+type ButtonEvent struct {
+	Type   ButtonEventType
+	Button int           // Button index (0 = K1), for chords the button that completed it
+	Mask   uint          // Buttons involved, bit i for button i (see ButtonMask)
+	Hold   time.Duration // How long Button has been down
+	Count  int           // Repeat number, from 1
+	Time   time.Time
+}
+
+// String - Event description for logs, e.g. "K1 long-press" or "K1+K3 chord"
+func (ev ButtonEvent) String() string {
+	name := ""
+	for i := 0; ev.Mask>>uint(i) != 0; i++ {
+		if ev.Mask&(1<<uint(i)) != 0 {
+			if name != "" {
+				name += "+"
+			}
+			name += fmt.Sprintf("K%d", i+1)
+		}
+	}
+	return name + " " + ev.Type.String()
+}
+
+// ButtonMask - Mask of the given button indexes, to compare with ButtonEvent.Mask
+func ButtonMask(buttons ...int) uint {
+	var mask uint
+	for _, b := range buttons {
+		mask |= 1 << uint(b)
+	}
+	return mask
+}
+
+// ButtonTiming - Durations the ButtonManager works with, zero disables a feature
+type ButtonTiming struct {
+	Debounce       time.Duration // Level must settle this long before a change counts
+	LongPress      time.Duration // Hold time before ButtonLongPress, 0 never reports one
+	RepeatInterval time.Duration // Period of ButtonRepeat after a long press, 0 disables repeat
+	DoubleClick    time.Duration // Window for a second click; clicks wait for it to pass, 0 disables double clicks
+}
+
+// DefaultButtonTiming - Timing suited to the NanoHat tact switches
+func DefaultButtonTiming() ButtonTiming {
+	return ButtonTiming{
+		Debounce:       20 * time.Millisecond,
+		LongPress:      800 * time.Millisecond,
+		RepeatInterval: 200 * time.Millisecond,
+		DoubleClick:    250 * time.Millisecond,
+	}
+}
+
+// buttonEdge - Debounced level change of one button
+type buttonEdge struct {
+	button  int
+	pressed bool
+	time    time.Time
+}
+
+// buttonState - Per-button state of the event machine
+type buttonState struct {
+	down       bool
+	since      time.Time // When it went down
+	long       bool      // Long press reported for this hold
+	chorded    bool      // Part of a chord during this hold, no click or long press
+	second     bool      // This hold is the second half of a double click
+	repeats    int
+	nextRepeat time.Time
+	clickAt    time.Time // Pending click is reported then, zero when none is pending
+	clickHold  time.Duration
+}
+
+// ButtonManager - Turns button levels into press, release, click, long press, repeat,
+// double click and chord events. Levels come from GPIO pins (WatchPin) or Feed.
+type ButtonManager struct {
+	ctx    context.Context
+	timing ButtonTiming
+	edges  chan buttonEdge
+	events chan ButtonEvent
+	state  map[int]*buttonState
+	log    Logger // Errors of the GPIO watchers, may be nil
+}
+
+// NewButtonManager - Start a manager, it stops and closes Events when ctx ends
+func NewButtonManager(ctx context.Context, timing ButtonTiming) *ButtonManager {
+	m := &ButtonManager{
+		ctx:    ctx,
+		timing: timing,
+		edges:  make(chan buttonEdge, 16),
+		events: make(chan ButtonEvent, 16),
+		state:  map[int]*buttonState{},
+	}
+	go m.run()
+	return m
+}
+
+// Events - Channel the events are delivered on; read it promptly, timing stalls while it is full
+func (m *ButtonManager) Events() <-chan ButtonEvent {
+	return m.events
+}
+
+// Feed - Report the debounced level of button, for sources other than GPIO pins
+func (m *ButtonManager) Feed(button int, pressed bool) {
+	select {
+	case m.edges <- buttonEdge{button, pressed, time.Now()}:
+	case <-m.ctx.Done():
+	}
+}
+
+// WatchPin - Feed the level of pin as button from a goroutine, until the manager stops.
+// The pin must be set up for both edges.
+func (m *ButtonManager) WatchPin(button int, pin gpio.PinIO, activeLow bool) {
+	pressedLevel := gpio.High
+	if activeLow {
+		pressedLevel = gpio.Low
+	}
+	go func() {
+		last := pin.Read() == pressedLevel
+		if last {
+			m.Feed(button, true)
+		}
+		for m.ctx.Err() == nil {
+			// Time out now and then to notice the end of ctx and any missed edge
+			if pin.WaitForEdge(500*time.Millisecond) && m.timing.Debounce > 0 {
+				time.Sleep(m.timing.Debounce)
+			}
+			if level := pin.Read() == pressedLevel; level != last {
+				last = level
+				m.Feed(button, level)
+			}
+		}
+	}()
+}
+
+// run - Event machine, owns all button state
+func (m *ButtonManager) run() {
+	defer close(m.events)
+	timer := time.NewTimer(time.Hour)
+	defer timer.Stop()
+
+	for {
+		timer.Reset(m.nextDeadline())
+		select {
+		case <-m.ctx.Done():
+			return
+		case edge := <-m.edges:
+			if !timer.Stop() {
+				<-timer.C
+			}
+			m.expire(edge.time)
+			m.edge(edge)
+		case now := <-timer.C:
+			m.expire(now)
+		}
+	}
+}
+
+// emit - Deliver an event, giving up when the manager stops
+func (m *ButtonManager) emit(ev ButtonEvent) {
+	if ev.Mask == 0 {
+		ev.Mask = ButtonMask(ev.Button)
+	}
+	select {
+	case m.events <- ev:
+	case <-m.ctx.Done():
+	}
+}
+
+// edge - Apply a level change of one button
+func (m *ButtonManager) edge(e buttonEdge) {
+	st := m.state[e.button]
+	if st == nil {
+		st = &buttonState{}
+		m.state[e.button] = st
+	}
+	if st.down == e.pressed {
+		return
+	}
+	st.down = e.pressed
+
+	if e.pressed {
+		st.since, st.long, st.chorded, st.repeats = e.time, false, false, 0
+		st.second = !st.clickAt.IsZero()
+		st.clickAt = time.Time{}
+		m.emit(ButtonEvent{Type: ButtonPress, Button: e.button, Time: e.time})
+
+		var held uint
+		for b, s := range m.state {
+			if s.down {
+				held |= ButtonMask(b)
+			}
+		}
+		if held != ButtonMask(e.button) {
+			for _, s := range m.state {
+				if s.down {
+					s.chorded, s.second, s.clickAt = true, false, time.Time{}
+				}
+			}
+			m.emit(ButtonEvent{Type: ButtonChord, Button: e.button, Mask: held, Time: e.time})
+		}
+		return
+	}
+
+	hold := e.time.Sub(st.since)
+	m.emit(ButtonEvent{Type: ButtonRelease, Button: e.button, Hold: hold, Time: e.time})
+	switch {
+	case st.chorded || st.long:
+	case st.second:
+		m.emit(ButtonEvent{Type: ButtonDoubleClick, Button: e.button, Hold: hold, Time: e.time})
+	case m.timing.DoubleClick > 0:
+		st.clickAt, st.clickHold = e.time.Add(m.timing.DoubleClick), hold
+	default:
+		m.emit(ButtonEvent{Type: ButtonClick, Button: e.button, Hold: hold, Time: e.time})
+	}
+	st.second = false
+}
+
+// expire - Report clicks, long presses and repeats that are due at now
+func (m *ButtonManager) expire(now time.Time) {
+	for b, st := range m.state {
+		if !st.clickAt.IsZero() && !now.Before(st.clickAt) {
+			st.clickAt = time.Time{}
+			m.emit(ButtonEvent{Type: ButtonClick, Button: b, Hold: st.clickHold, Time: now})
+		}
+		if !st.down || st.chorded || m.timing.LongPress <= 0 {
+			continue
+		}
+		if !st.long && now.Sub(st.since) >= m.timing.LongPress {
+			st.long = true
+			st.nextRepeat = st.since.Add(m.timing.LongPress + m.timing.RepeatInterval)
+			m.emit(ButtonEvent{Type: ButtonLongPress, Button: b, Hold: now.Sub(st.since), Time: now})
+		}
+		for st.long && m.timing.RepeatInterval > 0 && !now.Before(st.nextRepeat) {
+			st.repeats++
+			st.nextRepeat = st.nextRepeat.Add(m.timing.RepeatInterval)
+			m.emit(ButtonEvent{Type: ButtonRepeat, Button: b, Hold: now.Sub(st.since), Count: st.repeats, Time: now})
+		}
+	}
+}
+
+// nextDeadline - Time until the next click, long press or repeat is due
+func (m *ButtonManager) nextDeadline() time.Duration {
+	next := time.Hour
+	due := func(at time.Time) {
+		if d := time.Until(at); d < next {
+			next = d
+		}
+	}
+	for _, st := range m.state {
+		if !st.clickAt.IsZero() {
+			due(st.clickAt)
+		}
+		if !st.down || st.chorded || m.timing.LongPress <= 0 {
+			continue
+		}
+		if !st.long {
+			due(st.since.Add(m.timing.LongPress))
+		} else if m.timing.RepeatInterval > 0 {
+			due(st.nextRepeat)
+		}
+	}
+	if next < 0 {
+		next = 0
+	}
+	return next
+}
+
+// Buttons - Manager fed by the GPIO buttons, without any on a virtual display
+// or when opened with NoButtons (Feed still works)
+func (nanoOled *NanoOled) Buttons(ctx context.Context, timing ButtonTiming) *ButtonManager {
+	m := NewButtonManager(ctx, timing)
+	m.log = nanoOled.opts.Logger
+	if nanoOled.btnLines != nil {
+		m.watchLines(nanoOled.btnLines)
+		return m
+	}
+	for i, pin := range nanoOled.Btn {
+		if pin != nil {
+			m.WatchPin(i, pin, nanoOled.btnActiveLow)
+		}
+	}
+	return m
+}
@@ -0,0 +1,112 @@
+package nanohatoled
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// feedStep - Level change fed to the manager, then a pause
+type feedStep struct {
+	button  int
+	pressed bool
+	wait    time.Duration
+}
+
+// wantEvent - Fields of a ButtonEvent a test checks
+type wantEvent struct {
+	typ    ButtonEventType
+	button int
+	mask   uint
+	count  int
+}
+
+func TestButtonManager(t *testing.T) {
+	timing := ButtonTiming{
+		LongPress:      200 * time.Millisecond,
+		RepeatInterval: 100 * time.Millisecond,
+		DoubleClick:    80 * time.Millisecond,
+	}
+	ms := time.Millisecond
+	cases := []struct {
+		name  string
+		steps []feedStep
+		want  []wantEvent
+	}{
+		{
+			name:  "click",
+			steps: []feedStep{{0, true, 20 * ms}, {0, false, 0}},
+			want: []wantEvent{
+				{ButtonPress, 0, 1, 0}, {ButtonRelease, 0, 1, 0}, {ButtonClick, 0, 1, 0},
+			},
+		},
+		{
+			name:  "double click",
+			steps: []feedStep{{1, true, 20 * ms}, {1, false, 20 * ms}, {1, true, 20 * ms}, {1, false, 0}},
+			want: []wantEvent{
+				{ButtonPress, 1, 2, 0}, {ButtonRelease, 1, 2, 0},
+				{ButtonPress, 1, 2, 0}, {ButtonRelease, 1, 2, 0}, {ButtonDoubleClick, 1, 2, 0},
+			},
+		},
+		{
+			name:  "clicks outside the double click window",
+			steps: []feedStep{{1, true, 20 * ms}, {1, false, 150 * ms}, {1, true, 20 * ms}, {1, false, 0}},
+			want: []wantEvent{
+				{ButtonPress, 1, 2, 0}, {ButtonRelease, 1, 2, 0}, {ButtonClick, 1, 2, 0},
+				{ButtonPress, 1, 2, 0}, {ButtonRelease, 1, 2, 0}, {ButtonClick, 1, 2, 0},
+			},
+		},
+		{
+			name:  "chord",
+			steps: []feedStep{{0, true, 10 * ms}, {2, true, 20 * ms}, {0, false, 10 * ms}, {2, false, 0}},
+			want: []wantEvent{
+				{ButtonPress, 0, 1, 0}, {ButtonPress, 2, 4, 0}, {ButtonChord, 2, 5, 0},
+				{ButtonRelease, 0, 1, 0}, {ButtonRelease, 2, 4, 0},
+			},
+		},
+		{
+			name:  "long press with repeats",
+			steps: []feedStep{{2, true, 450 * ms}, {2, false, 0}},
+			want: []wantEvent{
+				{ButtonPress, 2, 4, 0}, {ButtonLongPress, 2, 4, 0},
+				{ButtonRepeat, 2, 4, 1}, {ButtonRepeat, 2, 4, 2}, {ButtonRelease, 2, 4, 0},
+			},
+		},
+	}
+
+	for _, c := range cases {
+		c := c
+		t.Run(c.name, func(t *testing.T) {
+			t.Parallel()
+			ctx, cancel := context.WithCancel(context.Background())
+			m := NewButtonManager(ctx, timing)
+			var got []ButtonEvent
+			done := make(chan struct{})
+			go func() {
+				for ev := range m.Events() {
+					got = append(got, ev)
+				}
+				close(done)
+			}()
+
+			for _, step := range c.steps {
+				m.Feed(step.button, step.pressed)
+				time.Sleep(step.wait)
+			}
+			time.Sleep(2 * timing.DoubleClick) // Let pending clicks come out
+			cancel()
+			<-done
+
+			if len(got) != len(c.want) {
+				t.Fatalf("got %v, want %d events", got, len(c.want))
+			}
+			for i, w := range c.want {
+				ev := got[i]
+				if ev.Type != w.typ || ev.Button != w.button || ev.Mask != w.mask || ev.Count != w.count {
+					t.Errorf("event %d: got %v (button %d, mask %b, count %d), want %v (button %d, mask %b, count %d)",
+						i, ev.Type, ev.Button, ev.Mask, ev.Count, w.typ, w.button, w.mask, w.count)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,256 @@
+package nanohatoled
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+// ButtonSource - Supplies button levels, e.g. to ButtonManager.Feed:
+//
+//	go src.Run(ctx, buttons.Feed)
+//
+// Besides the GPIO buttons (NanoOled.Buttons) there are scripted and text driven
+// sources for development and tests. Their scripts are words separated by spaces:
+//
+//	1, k1          click K1 (held for 50ms)
+//	1+3            press K1 and K3 together, then release both
+//	2:1s           hold K2 for a second
+//	down:1, up:1   press or release only
+//	wait:300ms     pause
+//
+// Every word ends with a 50ms pause, so "1 1" is two clicks 100ms apart (a double
+// click when the manager allows them).
+type ButtonSource interface {
+	// Run feeds levels until the source ends, the error is nil when it simply ran out
+	Run(ctx context.Context, feed func(button int, pressed bool)) error
+}
+
+// Script timing
+const (
+	scriptClickHold = 50 * time.Millisecond // Hold time of a plain click
+	scriptWordGap   = 50 * time.Millisecond // Pause after every word
+)
+
+// buttonStep - One action of a script: change the buttons in mask, or just wait
+type buttonStep struct {
+	mask    uint
+	pressed bool
+	wait    time.Duration
+}
+
+// parseButtonKeys - Mask of keys like "1", "k3" or "1+3"
+func parseButtonKeys(keys string) (uint, error) {
+	var mask uint
+	for _, key := range strings.Split(keys, "+") {
+		n, err := strconv.Atoi(strings.TrimPrefix(strings.ToLower(key), "k"))
+		if err != nil || n < 1 || n > 3 {
+			return 0, fmt.Errorf("unknown button %q", key)
+		}
+		mask |= ButtonMask(n - 1)
+	}
+	return mask, nil
+}
+
+// parseButtonScript - Steps for script, see ButtonSource
+func parseButtonScript(script string) ([]buttonStep, error) {
+	var steps []buttonStep
+	for _, word := range strings.Fields(script) {
+		name, arg, hasArg := strings.Cut(word, ":")
+		switch strings.ToLower(name) {
+		case "wait":
+			d, err := time.ParseDuration(arg)
+			if err != nil || d < 0 {
+				return nil, fmt.Errorf("bad wait %q", word)
+			}
+			steps = append(steps, buttonStep{wait: d})
+		case "down", "up":
+			mask, err := parseButtonKeys(arg)
+			if err != nil {
+				return nil, err
+			}
+			steps = append(steps, buttonStep{mask: mask, pressed: name == "down"})
+		default:
+			mask, err := parseButtonKeys(name)
+			if err != nil {
+				return nil, err
+			}
+			hold := scriptClickHold
+			if hasArg {
+				if hold, err = time.ParseDuration(arg); err != nil || hold < 0 {
+					return nil, fmt.Errorf("bad hold time %q", word)
+				}
+			}
+			steps = append(steps,
+				buttonStep{mask: mask, pressed: true},
+				buttonStep{wait: hold},
+				buttonStep{mask: mask, pressed: false})
+		}
+		steps = append(steps, buttonStep{wait: scriptWordGap})
+	}
+	return steps, nil
+}
+
+// runButtonSteps - Feed steps in order, false when ctx ended first
+func runButtonSteps(ctx context.Context, steps []buttonStep, feed func(button int, pressed bool)) bool {
+	for _, step := range steps {
+		for b := 0; step.mask>>uint(b) != 0; b++ {
+			if step.mask&ButtonMask(b) != 0 {
+				feed(b, step.pressed)
+			}
+		}
+		if step.wait > 0 {
+			select {
+			case <-time.After(step.wait):
+			case <-ctx.Done():
+				return false
+			}
+		}
+	}
+	return ctx.Err() == nil
+}
+
+// scriptSource - Fixed sequence, see ScriptSource
+type scriptSource struct {
+	steps []buttonStep
+}
+
+// ScriptSource - Source that plays script once (see ButtonSource for the syntax)
+func ScriptSource(script string) (ButtonSource, error) {
+	steps, err := parseButtonScript(script)
+	if err != nil {
+		return nil, err
+	}
+	return &scriptSource{steps}, nil
+}
+
+// Run - Play the script
+func (src *scriptSource) Run(ctx context.Context, feed func(button int, pressed bool)) error {
+	runButtonSteps(ctx, src.steps, feed)
+	return nil
+}
+
+// readerSource - Scripts read line by line, see ReaderSource
+type readerSource struct {
+	r     io.Reader
+	reply io.Writer // Told about bad lines, may be nil
+}
+
+// ReaderSource - Source that plays every line read from r as a script, e.g. typed on a
+// terminal (os.Stdin). Bad lines are skipped. Run returns at the end of r; to stop it
+// earlier, close r.
+func ReaderSource(r io.Reader) ButtonSource {
+	return &readerSource{r: r}
+}
+
+// Run - Play lines until the end of the reader
+func (src *readerSource) Run(ctx context.Context, feed func(button int, pressed bool)) error {
+	scanner := bufio.NewScanner(src.r)
+	for ctx.Err() == nil && scanner.Scan() {
+		steps, err := parseButtonScript(scanner.Text())
+		if err != nil {
+			if src.reply != nil {
+				fmt.Fprintf(src.reply, "error: %v\n", err)
+			}
+			continue
+		}
+		runButtonSteps(ctx, steps, feed)
+	}
+	if ctx.Err() != nil {
+		return nil
+	}
+	return scanner.Err()
+}
+
+// closeOnDone - Close c when ctx ends, until stop is called
+func closeOnDone(ctx context.Context, c io.Closer) (stop func()) {
+	done := make(chan struct{})
+	go func() {
+		select {
+		case <-ctx.Done():
+			c.Close()
+		case <-done:
+		}
+	}()
+	return func() { close(done) }
+}
+
+// fifoSource - Named pipe, see FIFOSource
+type fifoSource struct {
+	path string
+}
+
+// FIFOSource - Source reading scripts from a named pipe at path, created when missing,
+// e.g. echo "3 1 2" > path. Writers may come and go until ctx ends.
+func FIFOSource(path string) ButtonSource {
+	return &fifoSource{path}
+}
+
+// Run - Read the pipe until ctx ends
+func (src *fifoSource) Run(ctx context.Context, feed func(button int, pressed bool)) error {
+	if err := unix.Mkfifo(src.path, 0600); err != nil && !os.IsExist(err) {
+		return fmt.Errorf("create FIFO %s failed: %w", src.path, err)
+	}
+	// Opened for writing too, so the pipe never reports end of file between writers
+	f, err := os.OpenFile(src.path, os.O_RDWR, 0)
+	if err != nil {
+		return err
+	}
+	stop := closeOnDone(ctx, f)
+	defer stop()
+	defer f.Close()
+	return (&readerSource{r: f}).Run(ctx, feed)
+}
+
+// socketSource - Unix socket, see SocketSource
+type socketSource struct {
+	path string
+}
+
+// SocketSource - Source listening on a unix socket at path, each connection sends
+// scripts line by line (e.g. echo 2 | nc -U path) and is told about bad ones
+func SocketSource(path string) ButtonSource {
+	return &socketSource{path}
+}
+
+// Run - Serve connections until ctx ends
+func (src *socketSource) Run(ctx context.Context, feed func(button int, pressed bool)) error {
+	if fi, err := os.Lstat(src.path); err == nil && fi.Mode()&os.ModeSocket != 0 {
+		os.Remove(src.path) // Left over from an earlier run
+	}
+	// Only the owner may press buttons: the daemon runs with umask 0, and a socket
+	// chmodded after Listen would be open to everyone for a moment
+	mask := unix.Umask(0077)
+	ln, err := net.Listen("unix", src.path)
+	unix.Umask(mask)
+	if err != nil {
+		return err
+	}
+	stop := closeOnDone(ctx, ln)
+	defer stop()
+	defer ln.Close()
+
+	for {
+		conn, err := ln.Accept()
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+		go func() {
+			stop := closeOnDone(ctx, conn)
+			defer stop()
+			defer conn.Close()
+			(&readerSource{r: conn, reply: conn}).Run(ctx, feed)
+		}()
+	}
+}
@@ -0,0 +1,77 @@
+package nanohatoled
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+func TestParseButtonScript(t *testing.T) {
+	gap := buttonStep{wait: scriptWordGap}
+	click := func(mask uint, hold time.Duration) []buttonStep {
+		return []buttonStep{{mask: mask, pressed: true}, {wait: hold}, {mask: mask}, gap}
+	}
+	cases := []struct {
+		script string
+		want   []buttonStep
+	}{
+		{"", nil},
+		{"1", click(1, scriptClickHold)},
+		{"K3", click(4, scriptClickHold)},
+		{"1+3", click(5, scriptClickHold)},
+		{"2:1s", click(2, time.Second)},
+		{"down:1 up:1", []buttonStep{{mask: 1, pressed: true}, gap, {mask: 1}, gap}},
+		{"wait:300ms", []buttonStep{{wait: 300 * time.Millisecond}, gap}},
+		{"1  2", append(click(1, scriptClickHold), click(2, scriptClickHold)...)},
+	}
+	for _, c := range cases {
+		got, err := parseButtonScript(c.script)
+		if err != nil {
+			t.Errorf("%q: %v", c.script, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, c.want) {
+			t.Errorf("%q: got %+v, want %+v", c.script, got, c.want)
+		}
+	}
+
+	for _, bad := range []string{"k4", "0", "x", "1+", "wait:-1s", "wait:soon", "2:x", "2:-1s", "down:", "up:k9"} {
+		if _, err := parseButtonScript(bad); err == nil {
+			t.Errorf("%q: no error", bad)
+		}
+	}
+}
+
+func TestSocketSourceMode(t *testing.T) {
+	// The daemon's umask, so the mode has to come from the source itself
+	mask := unix.Umask(0)
+	defer unix.Umask(mask)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	path := filepath.Join(t.TempDir(), "buttons.sock")
+	done := make(chan error, 1)
+	go func() { done <- SocketSource(path).Run(ctx, func(int, bool) {}) }()
+
+	// Check the first mode the socket shows up with
+	deadline := time.Now().Add(time.Second)
+	fi, err := os.Stat(path)
+	for err != nil && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+		fi, err = os.Stat(path)
+	}
+	cancel()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if perm := fi.Mode().Perm(); perm&0077 != 0 {
+		t.Errorf("socket mode %v, want no group or other access", perm)
+	}
+	if err := <-done; err != nil {
+		t.Error(err)
+	}
+}
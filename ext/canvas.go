@@ -0,0 +1,161 @@
+package nanohatoled
+
+import (
+	"fmt"
+	"image"
+
+	"github.com/golang/freetype/truetype"
+)
+
+// BlitMode - How Blit combines source pixels with the destination
+type BlitMode int
+
+const (
+	BlitCopy BlitMode = iota // Destination takes the source pixel
+	BlitOr                   // Lit source pixels light the destination
+	BlitAnd                  // Unlit source pixels clear the destination
+	BlitXor                  // Lit source pixels toggle the destination
+)
+
+// String - Mode name
+func (mode BlitMode) String() string {
+	switch mode {
+	case BlitCopy:
+		return "copy"
+	case BlitOr:
+		return "or"
+	case BlitAnd:
+		return "and"
+	case BlitXor:
+		return "xor"
+	}
+	return fmt.Sprintf("BlitMode(%d)", int(mode))
+}
+
+// resources - Fonts, face cache and icons shared by a display and all its canvases
+type resources struct {
+	normalFont *truetype.Font // Regular monospace font
+	boldFont   *truetype.Font // Bold monospace font
+	fallback   []Font         // Fonts for runes the current font lacks, in order
+	faces      map[faceKey]*cachedFace
+	icons      map[iconKey]*Mono // Icons added through AddIcon and LoadIcons
+}
+
+// Canvas - Drawing surface for text, shapes, icons and pictures. The display itself is
+// the root canvas; NewCanvas makes off-screen ones and Viewport restricts drawing to
+// part of a canvas, so widgets can render independently and never paint outside their box.
+type Canvas struct {
+	image  *Mono           // Pixels drawn into, shared by viewports
+	origin image.Point     // Position of this canvas's (0, 0) in image
+	clip   image.Rectangle // Part of image this canvas may change
+	size   image.Point     // Width and height seen by the drawing methods
+
+	res *resources
+
+	// Font related fields
+	currentFont *truetype.Font // Currently used font
+	fontSize    float64        // Current font size
+	bold        bool           // Bold requested through SetBold
+	font        Font           // Font set by SetFont, nil for the built-in fonts
+}
+
+// reset - Draw into m from now on, covering all of it
+func (canvas *Canvas) reset(m *Mono) {
+	canvas.image = m
+	canvas.origin = m.Rect.Min
+	canvas.clip = m.Rect
+	canvas.size = m.Rect.Size()
+}
+
+// derive - Canvas with the same fonts and font settings drawing into m
+func (canvas *Canvas) derive(m *Mono) *Canvas {
+	c := *canvas
+	c.reset(m)
+	return &c
+}
+
+// NewCanvas - Blank off-screen w x h canvas with this canvas's fonts and font settings
+func (canvas *Canvas) NewCanvas(w int, h int) *Canvas {
+	if w < 0 {
+		w = 0
+	}
+	if h < 0 {
+		h = 0
+	}
+	return canvas.derive(NewMono(w, h))
+}
+
+// Viewport - Canvas for the rectangle r of this canvas: its (0, 0) is r.Min and drawing
+// is clipped to r. It draws into the current frame, so take it again after New or Clear.
+func (canvas *Canvas) Viewport(r image.Rectangle) *Canvas {
+	r = r.Canon()
+	vp := *canvas
+	vp.origin = canvas.origin.Add(r.Min)
+	vp.clip = canvas.clip.Intersect(r.Add(canvas.origin))
+	vp.size = r.Size()
+	return &vp
+}
+
+// Size - Canvas width and height in pixels
+func (canvas *Canvas) Size() (int, int) {
+	return canvas.size.X, canvas.size.Y
+}
+
+// Mono - Pixels of the whole underlying image (for a viewport, its parent's too)
+func (canvas *Canvas) Mono() *Mono {
+	return canvas.image
+}
+
+// bounds - Clip rectangle in canvas coordinates
+func (canvas *Canvas) bounds() image.Rectangle {
+	return canvas.clip.Sub(canvas.origin)
+}
+
+// set - Light or clear pixel (x, y) of the canvas, ignored outside the clip rectangle
+func (canvas *Canvas) set(x, y int, on bool) {
+	p := image.Point{X: x + canvas.origin.X, Y: y + canvas.origin.Y}
+	if p.In(canvas.clip) {
+		canvas.image.SetBit(p.X, p.Y, on)
+	}
+}
+
+// get - Whether pixel (x, y) of the canvas is lit, false outside the clip rectangle
+func (canvas *Canvas) get(x, y int) bool {
+	p := image.Point{X: x + canvas.origin.X, Y: y + canvas.origin.Y}
+	return p.In(canvas.clip) && canvas.image.Bit(p.X, p.Y)
+}
+
+// Fill - Set every pixel of the canvas to on
+func (canvas *Canvas) Fill(on bool) {
+	b := canvas.bounds()
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		canvas.hspan(b.Min.X, b.Max.X-1, y, on)
+	}
+}
+
+// Blit - Combine src into this canvas with src's (0, 0) at (x, y)
+func (canvas *Canvas) Blit(x int, y int, src *Canvas, mode BlitMode) {
+	sb := src.bounds()
+	for sy := sb.Min.Y; sy < sb.Max.Y; sy++ {
+		for sx := sb.Min.X; sx < sb.Max.X; sx++ {
+			lit := src.get(sx, sy)
+			dx, dy := x+sx, y+sy
+			switch mode {
+			case BlitCopy:
+				canvas.set(dx, dy, lit)
+			case BlitOr:
+				if lit {
+					canvas.set(dx, dy, true)
+				}
+			case BlitAnd:
+				if !lit {
+					canvas.set(dx, dy, false)
+				}
+			case BlitXor:
+				if lit {
+					canvas.set(dx, dy, !canvas.get(dx, dy))
+				}
+			}
+		}
+	}
+}
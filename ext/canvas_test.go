@@ -0,0 +1,79 @@
+package nanohatoled
+
+import (
+	"image"
+	"testing"
+)
+
+func TestViewportClipsAndTranslates(t *testing.T) {
+	c := testCanvas(t, 8, 6)
+	vp := c.Viewport(image.Rect(2, 1, 6, 4))
+	if w, h := vp.Size(); w != 4 || h != 3 {
+		t.Errorf("viewport size %dx%d", w, h)
+	}
+	vp.Pixel(0, 0, true)
+	vp.Line(-10, 2, 10, 2, true)
+	vp.Rect(3, -5, 20, 20, true)
+	vp.Pixel(-1, 1, true) // Outside, left of the viewport
+	vp.Pixel(4, 1, true)  // Outside, right of the viewport
+	checkRows(t, "viewport", c,
+		"........",
+		"..#..#..",
+		".....#..",
+		"..####..",
+		"........",
+		"........")
+
+	// Nested viewports stay inside their parent, also when reaching past the canvas
+	c = testCanvas(t, 8, 6)
+	inner := c.Viewport(image.Rect(4, 3, 20, 20)).Viewport(image.Rect(-1, -1, 2, 2))
+	inner.Fill(true)
+	checkRows(t, "nested", c,
+		"........",
+		"........",
+		"........",
+		"....##..",
+		"....##..",
+		"........")
+	// Inner (0, 0) is left of and above the outer viewport
+	if !inner.get(1, 1) || inner.get(0, 0) {
+		t.Error("nested viewport reads outside its clip")
+	}
+}
+
+func TestBlitModes(t *testing.T) {
+	// Source: outer columns lit, middle one dark
+	src := testCanvas(t, 3, 3)
+	src.LineV(0, 0, 3, true)
+	src.LineV(2, 0, 3, true)
+
+	// The source hangs over the left edge at (-1, 1) and over the right one at (3, 1),
+	// so rows 1-3 get dark at column 0 and lit at columns 1 and 3
+	cases := []struct {
+		mode BlitMode
+		want []string
+	}{
+		{BlitCopy, []string{"####", ".###", ".#.#", ".#.#"}},
+		{BlitOr, []string{"####", "####", ".#.#", ".#.#"}},
+		{BlitAnd, []string{"####", ".###", "....", "...."}},
+		{BlitXor, []string{"####", "#.#.", ".#.#", ".#.#"}},
+	}
+	for _, c := range cases {
+		// Top half lit, bottom half dark
+		dst := testCanvas(t, 4, 4)
+		dst.Rect(0, 0, 3, 1, true)
+		dst.Blit(-1, 1, src, c.mode)
+		dst.Blit(3, 1, src, c.mode)
+		checkRows(t, c.mode.String(), dst, c.want...)
+	}
+
+	// Past the bottom right corner only the overlap changes
+	dst := testCanvas(t, 4, 4)
+	dst.Blit(2, 2, src, BlitCopy)
+	dst.Blit(3, -2, src, BlitXor)
+	checkRows(t, "corner", dst,
+		"...#",
+		"....",
+		"..#.",
+		"..#.")
+}
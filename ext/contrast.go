@@ -0,0 +1,65 @@
+package nanohatoled
+
+import "fmt"
+
+// VCOMH deselect levels (SSD1306 datasheet values, other controllers take raw values)
+const (
+	VCOMH065 byte = 0x00 // ~0.65 x Vcc
+	VCOMH077 byte = 0x20 // ~0.77 x Vcc
+	VCOMH083 byte = 0x30 // ~0.83 x Vcc
+)
+
+// SetContrast - Set panel contrast (0 dimmest, 255 brightest), kept across re-init
+func (nanoOled *NanoOled) SetContrast(level uint8) error {
+	if err := writeCommands(nanoOled.dev, 0x81, level); err != nil {
+		return fmt.Errorf("set contrast failed: %w", err)
+	}
+	nanoOled.contrast = level
+	return nil
+}
+
+// Contrast - Current panel contrast
+func (nanoOled *NanoOled) Contrast() uint8 {
+	return nanoOled.contrast
+}
+
+// SetPrecharge - Set pre-charge phase 1 and phase 2 periods in display clocks (1-15 each).
+// Longer periods raise brightness, shorter ones reduce power and glow at low contrast.
+// Kept across re-init.
+func (nanoOled *NanoOled) SetPrecharge(phase1, phase2 uint8) error {
+	if phase1 < 1 || phase1 > 15 || phase2 < 1 || phase2 > 15 {
+		return fmt.Errorf("pre-charge periods %d/%d out of range 1-15", phase1, phase2)
+	}
+	if err := writeCommands(nanoOled.dev, 0xd9, phase2<<4|phase1); err != nil {
+		return fmt.Errorf("set pre-charge failed: %w", err)
+	}
+	nanoOled.precharge = int(phase2<<4 | phase1)
+	return nil
+}
+
+// SetVCOMH - Set VCOMH deselect level (VCOMH065, VCOMH077, VCOMH083 or a raw register value),
+// kept across re-init. Bits the controller does not use are cleared: SSD1306 keeps A[6:4],
+// SSD1309 A[5:2] and SH1106 the whole byte.
+func (nanoOled *NanoOled) SetVCOMH(level byte) error {
+	level &= nanoOled.ctrl.vcomhMask()
+	if err := writeCommands(nanoOled.dev, 0xdb, level); err != nil {
+		return fmt.Errorf("set VCOMH failed: %w", err)
+	}
+	nanoOled.vcomh = int(level)
+	return nil
+}
+
+// restoreTuning - Re-send pre-charge and VCOMH set before init reset them
+func (nanoOled *NanoOled) restoreTuning() error {
+	if nanoOled.precharge >= 0 {
+		if err := writeCommands(nanoOled.dev, 0xd9, byte(nanoOled.precharge)); err != nil {
+			return fmt.Errorf("set pre-charge failed: %w", err)
+		}
+	}
+	if nanoOled.vcomh >= 0 {
+		if err := writeCommands(nanoOled.dev, 0xdb, byte(nanoOled.vcomh)); err != nil {
+			return fmt.Errorf("set VCOMH failed: %w", err)
+		}
+	}
+	return nil
+}
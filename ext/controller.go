@@ -0,0 +1,243 @@
+package nanohatoled
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Controller - Display driver IC fitted on the panel
+type Controller int
+
+const (
+	SSD1306 Controller = iota // Stock NanoHat OLED, internal charge pump
+	SH1106                    // 1.3" panels, 132 column RAM with page addressing
+	SSD1309                   // SSD1306 compatible command set, external VCC
+)
+
+const sh1106ColumnOffset = 2 // Visible 128 columns start at RAM column 2
+
+// String - Controller name as used in configuration
+func (c Controller) String() string {
+	switch c {
+	case SSD1306:
+		return "ssd1306"
+	case SH1106:
+		return "sh1106"
+	case SSD1309:
+		return "ssd1309"
+	}
+	return fmt.Sprintf("controller(%d)", int(c))
+}
+
+// ParseController - Look up a controller by name (case insensitive)
+func ParseController(name string) (Controller, error) {
+	for _, c := range []Controller{SSD1306, SH1106, SSD1309} {
+		if strings.EqualFold(name, c.String()) {
+			return c, nil
+		}
+	}
+	return SSD1306, fmt.Errorf("unknown controller %q", name)
+}
+
+// controller - Command set of a display driver IC
+type controller interface {
+	// init - Run power-up sequence and turn display on
+	init(dev bus, w, h int) error
+	// defaultContrast - Contrast programmed by init for a panel of height h
+	defaultContrast(h int) byte
+	// canScroll - Report whether the SSD1306 scroll commands are available
+	canScroll() bool
+	// scrollSetup - Scroll command cmd with its arguments for pages page0..page1: horizontal
+	// (0x26/0x27) or diagonal (0x29/0x2a, moving offset rows per step)
+	scrollSetup(cmd byte, page0, page1 int, speed ScrollSpeed, offset int) []byte
+	// vcomhMask - Bits of the VCOMH deselect level register (0xdb) the IC uses
+	vcomhMask() byte
+	// drawWindow - Write pages page0..page1, columns col0..col1 of the packed buffer (buf[0] is the 0x40 prefix)
+	drawWindow(dev bus, buf []byte, w, page0, page1, col0, col1 int) error
+}
+
+// newController - Command set implementation for c
+func newController(c Controller) (controller, error) {
+	switch c {
+	case SSD1306:
+		return ssd1306{}, nil
+	case SH1106:
+		return sh1106{}, nil
+	case SSD1309:
+		return ssd1309{}, nil
+	}
+	return nil, fmt.Errorf("unsupported controller %v", c)
+}
+
+// writeCommands - Send command bytes to the panel behind the 0x00 control byte
+func writeCommands(dev bus, cmds ...byte) error {
+	return dev.Write(append([]byte{0x00}, cmds...))
+}
+
+// comPinsConfig - COM pins hardware configuration for panel height
+func comPinsConfig(h int) byte {
+	if h == 32 {
+		return 0x02
+	}
+	return 0x12
+}
+
+// ssd1306 - Horizontal addressing mode, whole frame in one transfer
+type ssd1306 struct{}
+
+func (ssd1306) defaultContrast(h int) byte {
+	if h == 32 {
+		return 0x8f
+	}
+	return 0x7f
+}
+
+func (c ssd1306) init(dev bus, w, h int) error {
+	return writeCommands(dev,
+		ssd1306DisplayOff,
+		0xd5, 0x80, // Set display clock divide ratio
+		0xa8, uint8(h-1), // Set multiplex ratio
+		0xd3, 0x00, // Set display offset
+		0x40|0,     // Set start line
+		0x8d, 0x14, // Enable charge pump
+		0x20, 0x00, // Set horizontal addressing mode
+		0xa0|0x1,               // Set segment re-map
+		0xc8,                   // Set COM output scan direction
+		0xda, comPinsConfig(h), // Set COM pins hardware configuration
+		0x81, c.defaultContrast(h), // Set contrast control
+		0xd9, 0xf1, // Set pre-charge period
+		0xdb, 0x40, // Set VCOMH deselect level
+		0xa4,                    // Disable entire display on
+		0xa6,                    // Set normal display
+		ssd1306DeactivateScroll, // Deactivate scroll
+		ssd1306DisplayOn,
+	)
+}
+
+func (ssd1306) canScroll() bool { return true }
+
+func (ssd1306) vcomhMask() byte { return 0x70 } // A[6:4]
+
+func (ssd1306) scrollSetup(cmd byte, page0, page1 int, speed ScrollSpeed, offset int) []byte {
+	setup := []byte{cmd, 0x00, uint8(page0), byte(speed), uint8(page1)} // 0x00 is a dummy byte
+	if cmd == ssd1306RightHorizontalScroll || cmd == ssd1306LeftHorizontalScroll {
+		return append(setup, 0x00, 0xff) // Dummy bytes
+	}
+	return append(setup, uint8(offset))
+}
+
+func (ssd1306) drawWindow(dev bus, buf []byte, w, page0, page1, col0, col1 int) error {
+	if err := writeCommands(dev,
+		0xa4,                           // Normal display mode
+		0x40|0,                         // Set start line
+		0x21, uint8(col0), uint8(col1), // Set column range
+		0x22, uint8(page0), uint8(page1), // Set page range
+	); err != nil {
+		return fmt.Errorf("draw init failed: %w", err)
+	}
+
+	// Whole frame is already laid out as the transfer needs it
+	if page0 == 0 && col0 == 0 && col1 == w-1 && 1+(page1+1)*w == len(buf) {
+		return dev.Write(buf)
+	}
+
+	cols := col1 - col0 + 1
+	data := make([]byte, 1, 1+(page1-page0+1)*cols)
+	data[0] = 0x40 // Data command prefix
+	for p := page0; p <= page1; p++ {
+		data = append(data, buf[1+p*w+col0:1+p*w+col1+1]...)
+	}
+	return dev.Write(data)
+}
+
+// sh1106 - Page addressing only, one transfer per page
+type sh1106 struct{}
+
+func (sh1106) defaultContrast(h int) byte { return 0x80 }
+
+func (c sh1106) init(dev bus, w, h int) error {
+	return writeCommands(dev,
+		ssd1306DisplayOff,
+		0xd5, 0x80, // Set display clock divide ratio
+		0xa8, uint8(h-1), // Set multiplex ratio
+		0xd3, 0x00, // Set display offset
+		0x40|0,     // Set start line
+		0xad, 0x8b, // Enable DC-DC converter
+		0xa0|0x1,               // Set segment re-map
+		0xc8,                   // Set COM output scan direction
+		0xda, comPinsConfig(h), // Set COM pins hardware configuration
+		0x81, c.defaultContrast(h), // Set contrast control
+		0xd9, 0x22, // Set pre-charge period
+		0xdb, 0x35, // Set VCOMH deselect level
+		0xa4, // Disable entire display on
+		0xa6, // Set normal display
+		ssd1306DisplayOn,
+	)
+}
+
+func (sh1106) canScroll() bool { return false }
+
+func (sh1106) vcomhMask() byte { return 0xff } // Whole byte
+
+func (sh1106) scrollSetup(cmd byte, page0, page1 int, speed ScrollSpeed, offset int) []byte {
+	return nil
+}
+
+func (sh1106) drawWindow(dev bus, buf []byte, w, page0, page1, col0, col1 int) error {
+	col := sh1106ColumnOffset + col0
+	data := make([]byte, col1-col0+2)
+	data[0] = 0x40 // Data command prefix
+	for p := page0; p <= page1; p++ {
+		if err := writeCommands(dev,
+			0xb0|uint8(p),      // Set page address
+			uint8(col&0x0f),    // Set lower column address
+			0x10|uint8(col>>4), // Set higher column address
+		); err != nil {
+			return fmt.Errorf("draw init failed: %w", err)
+		}
+		copy(data[1:], buf[1+p*w+col0:1+p*w+col1+1])
+		if err := dev.Write(data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ssd1309 - SSD1306 addressing without the internal charge pump
+type ssd1309 struct {
+	ssd1306
+}
+
+func (ssd1309) defaultContrast(h int) byte { return 0xdf }
+
+func (ssd1309) vcomhMask() byte { return 0x3c } // A[5:2]
+
+// scrollSetup - Like SSD1306, but the scroll also takes a start and end column, and the
+// first diagonal argument is the horizontal step instead of a dummy byte
+func (ssd1309) scrollSetup(cmd byte, page0, page1 int, speed ScrollSpeed, offset int) []byte {
+	if cmd == ssd1306RightHorizontalScroll || cmd == ssd1306LeftHorizontalScroll {
+		return []byte{cmd, 0x00, uint8(page0), byte(speed), uint8(page1), 0x00, 0x00, 0x7f}
+	}
+	return []byte{cmd, 0x01, uint8(page0), byte(speed), uint8(page1), uint8(offset), 0x00, 0x7f}
+}
+
+func (c ssd1309) init(dev bus, w, h int) error {
+	return writeCommands(dev,
+		ssd1306DisplayOff,
+		0xd5, 0xa0, // Set display clock divide ratio
+		0xa8, uint8(h-1), // Set multiplex ratio
+		0xd3, 0x00, // Set display offset
+		0x40|0,     // Set start line
+		0x20, 0x00, // Set horizontal addressing mode
+		0xa0|0x1,               // Set segment re-map
+		0xc8,                   // Set COM output scan direction
+		0xda, comPinsConfig(h), // Set COM pins hardware configuration
+		0x81, c.defaultContrast(h), // Set contrast control
+		0xd9, 0x82, // Set pre-charge period
+		0xdb, 0x34, // Set VCOMH deselect level
+		0xa4,                    // Disable entire display on
+		0xa6,                    // Set normal display
+		ssd1306DeactivateScroll, // Deactivate scroll
+		ssd1306DisplayOn,
+	)
+}
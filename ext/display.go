@@ -0,0 +1,60 @@
+package nanohatoled
+
+import (
+	"context"
+	"image"
+)
+
+// Display - Drawing surface implemented by the hardware panel and the virtual backend
+type Display interface {
+	Clear() error
+	New(rotation int)
+	Send() error
+	Close() error
+	Size() (int, int)
+
+	Text(x int, y int, text string, textColor bool)
+	TextBox(x int, y int, w int, h int, text string, opts TextOptions, textColor bool)
+	MeasureText(text string) (int, int)
+	FitText(text string, maxWidth int) string
+	Rect(MinX int, MinY int, MaxX int, MaxY int, rectColor bool)
+	Pixel(x int, y int, pixColor bool)
+	LineH(x int, y int, length int, lineColor bool)
+	LineV(x int, y int, length int, lineColor bool)
+	Line(x0 int, y0 int, x1 int, y1 int, lineColor bool)
+	DashedLine(x0 int, y0 int, x1 int, y1 int, dash int, gap int, lineColor bool)
+	RectOutline(MinX int, MinY int, MaxX int, MaxY int, rectColor bool)
+	RoundRect(MinX int, MinY int, MaxX int, MaxY int, radius int, rectColor bool)
+	FillRoundRect(MinX int, MinY int, MaxX int, MaxY int, radius int, rectColor bool)
+	Circle(cx int, cy int, r int, lineColor bool)
+	FillCircle(cx int, cy int, r int, fillColor bool)
+	Ellipse(cx int, cy int, rx int, ry int, lineColor bool)
+	FillEllipse(cx int, cy int, rx int, ry int, fillColor bool)
+	Arc(cx int, cy int, r int, startDeg int, endDeg int, lineColor bool)
+	Triangle(x0 int, y0 int, x1 int, y1 int, x2 int, y2 int, lineColor bool)
+	FillTriangle(x0 int, y0 int, x1 int, y1 int, x2 int, y2 int, fillColor bool)
+	Polygon(points []image.Point, lineColor bool)
+	FillPolygon(points []image.Point, fillColor bool)
+	Image(imagePath string) error
+	ImageWithOptions(imagePath string, opts ImageOptions) error
+	DrawImage(x int, y int, w int, h int, img image.Image, opts ImageOptions)
+	DrawIcon(x int, y int, name string, size int, iconColor bool) error
+	Play(ctx context.Context, anim *Animation, x int, y int) error
+
+	Fill(on bool)
+	NewCanvas(w int, h int) *Canvas
+	Viewport(r image.Rectangle) *Canvas
+	Blit(x int, y int, src *Canvas, mode BlitMode)
+
+	SetFont(f Font)
+	SetFontSize(size float64)
+	SetBold(isBold bool)
+
+	On() error
+	Off() error
+	IsOn() bool
+	SetContrast(level uint8) error
+	Contrast() uint8
+}
+
+var _ Display = (*NanoOled)(nil)
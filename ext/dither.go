@@ -0,0 +1,160 @@
+package nanohatoled
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"strings"
+
+	"github.com/disintegration/imaging"
+)
+
+// DitherMode - Method used to reduce grayscale images to 1 bit
+type DitherMode int
+
+const (
+	DitherNone           DitherMode = iota // Hard threshold
+	DitherFloydSteinberg                   // Error diffusion, smooth gradients
+	DitherAtkinson                         // Error diffusion with higher contrast, good for logos
+	DitherBayer                            // Ordered 8x8 pattern, stable between animation frames
+)
+
+// String - Dither mode name as used in configuration
+func (d DitherMode) String() string {
+	switch d {
+	case DitherNone:
+		return "none"
+	case DitherFloydSteinberg:
+		return "floyd-steinberg"
+	case DitherAtkinson:
+		return "atkinson"
+	case DitherBayer:
+		return "bayer"
+	}
+	return fmt.Sprintf("dither(%d)", int(d))
+}
+
+// ParseDitherMode - Look up a dither mode by name (case insensitive)
+func ParseDitherMode(name string) (DitherMode, error) {
+	for _, d := range []DitherMode{DitherNone, DitherFloydSteinberg, DitherAtkinson, DitherBayer} {
+		if strings.EqualFold(name, d.String()) {
+			return d, nil
+		}
+	}
+	return DitherNone, fmt.Errorf("unknown dither mode %q", name)
+}
+
+// ImageOptions - Conversion settings for ImageWithOptions, DrawImage and Dither
+type ImageOptions struct {
+	Dither    DitherMode
+	Threshold uint8   // Gray level (1-255) splitting lit from unlit, 0 selects 128
+	Invert    bool    // Light dark areas of the source instead of bright ones
+	Gamma     float64 // Gamma correction before dithering (<1 darker, >1 brighter), 0 disables
+
+	// Placement, used by DrawImage and friends
+	Scale    ScaleMode   // How the picture fits its box
+	Mask     image.Image // Only draw where the mask is opaque
+	UseAlpha bool        // Use the picture's own alpha channel as mask
+}
+
+// bayer8 - 8x8 ordered dither matrix (values 0-63)
+var bayer8 = [8][8]int32{
+	{0, 32, 8, 40, 2, 34, 10, 42},
+	{48, 16, 56, 24, 50, 18, 58, 26},
+	{12, 44, 4, 36, 14, 46, 6, 38},
+	{60, 28, 52, 20, 62, 30, 54, 22},
+	{3, 35, 11, 43, 1, 33, 9, 41},
+	{51, 19, 59, 27, 49, 17, 57, 25},
+	{15, 47, 7, 39, 13, 45, 5, 37},
+	{63, 31, 55, 23, 61, 29, 53, 21},
+}
+
+// Dither - Convert img to a 1-bit image of the same size. Transparent areas count as black.
+func Dither(img image.Image, opts ImageOptions) *Mono {
+	if opts.Gamma > 0 && opts.Gamma != 1 {
+		img = imaging.AdjustGamma(img, opts.Gamma)
+	}
+
+	b := img.Bounds()
+	w, h := b.Dx(), b.Dy()
+	threshold := int32(opts.Threshold)
+	if threshold == 0 {
+		threshold = 128
+	}
+
+	// Gray levels (0-255) composited over black, with room for diffused error
+	levels := make([]int32, w*h)
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			v := int32(color.GrayModel.Convert(img.At(b.Min.X+x, b.Min.Y+y)).(color.Gray).Y)
+			if opts.Invert {
+				v = 255 - v
+			}
+			levels[x+y*w] = v
+		}
+	}
+
+	out := NewMono(w, h)
+	// diffuse - Spread quantization error to a neighbour if it exists
+	diffuse := func(x, y int, err int32) {
+		if x >= 0 && x < w && y < h {
+			levels[x+y*w] += err
+		}
+	}
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			v := levels[x+y*w]
+			switch opts.Dither {
+			case DitherBayer:
+				// Shift the threshold by the matrix cell, centred on the configured level
+				on := v*64 > (threshold-128)*64+bayer8[y&7][x&7]*256+128
+				out.SetBit(x, y, on)
+
+			case DitherFloydSteinberg, DitherAtkinson:
+				on := v >= threshold
+				out.SetBit(x, y, on)
+				err := v
+				if on {
+					err = v - 255
+				}
+				if opts.Dither == DitherFloydSteinberg {
+					diffuse(x+1, y, err*7/16)
+					diffuse(x-1, y+1, err*3/16)
+					diffuse(x, y+1, err*5/16)
+					diffuse(x+1, y+1, err*1/16)
+				} else {
+					// Atkinson passes on 6/8 of the error, keeping contrast high
+					e := err / 8
+					diffuse(x+1, y, e)
+					diffuse(x+2, y, e)
+					diffuse(x-1, y+1, e)
+					diffuse(x, y+1, e)
+					diffuse(x+1, y+1, e)
+					diffuse(x, y+2, e)
+				}
+
+			default:
+				out.SetBit(x, y, v >= threshold)
+			}
+		}
+	}
+	return out
+}
+
+// ImageWithOptions - Load an image, fit it to the screen with smooth resampling and
+// convert it to 1 bit as described by opts
+func (nanoOled *NanoOled) ImageWithOptions(imagePath string, opts ImageOptions) error {
+	img, err := imaging.Open(imagePath)
+	if err != nil {
+		return fmt.Errorf("open image failed: %w", err)
+	}
+
+	lw, lh := nanoOled.Size()
+	mono := Dither(imaging.Fit(img, lw, lh, imaging.Lanczos), opts)
+
+	binaryImg := nanoOled.newImage()
+	draw.Draw(binaryImg, mono.Bounds(), mono, image.Point{}, draw.Src)
+	nanoOled.reset(binaryImg)
+	return nil
+}
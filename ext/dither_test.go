@@ -0,0 +1,129 @@
+package nanohatoled
+
+import (
+	"image"
+	"image/color"
+	"strings"
+	"testing"
+)
+
+// monoRows - Pixels of m as strings, '#' lit and '.' dark
+func monoRows(m *Mono) []string {
+	out := make([]string, m.Rect.Dy())
+	for y := range out {
+		var b strings.Builder
+		for x := 0; x < m.Rect.Dx(); x++ {
+			if m.Bit(x, y) {
+				b.WriteByte('#')
+			} else {
+				b.WriteByte('.')
+			}
+		}
+		out[y] = b.String()
+	}
+	return out
+}
+
+// uniformGray - w x h image of a single gray level
+func uniformGray(w, h int, level uint8) *image.Gray {
+	img := image.NewGray(image.Rect(0, 0, w, h))
+	for i := range img.Pix {
+		img.Pix[i] = level
+	}
+	return img
+}
+
+// gradient - 64 x 8 ramp from black on the left to white on the right
+func gradient() *image.Gray {
+	img := image.NewGray(image.Rect(0, 0, 64, 8))
+	for y := 0; y < 8; y++ {
+		for x := 0; x < 64; x++ {
+			img.SetGray(x, y, color.Gray{Y: uint8(x * 255 / 63)})
+		}
+	}
+	return img
+}
+
+func TestDitherGradient(t *testing.T) {
+	for _, mode := range []DitherMode{DitherNone, DitherFloydSteinberg, DitherAtkinson, DitherBayer} {
+		m := Dither(gradient(), ImageOptions{Dither: mode})
+		var quarters [4]int
+		for y := 0; y < 8; y++ {
+			for x := 0; x < 64; x++ {
+				if m.Bit(x, y) {
+					quarters[x/16]++
+				}
+			}
+		}
+		if m.Bit(0, 0) || !m.Bit(63, 7) {
+			t.Errorf("%v: black end lit %v, white end lit %v", mode, m.Bit(0, 0), m.Bit(63, 7))
+		}
+		// Lighter quarters of the ramp light more pixels, half of them overall
+		for q := 1; q < 4; q++ {
+			if quarters[q] < quarters[q-1] {
+				t.Errorf("%v: lit per quarter %v", mode, quarters)
+				break
+			}
+		}
+		if total := quarters[0] + quarters[1] + quarters[2] + quarters[3]; total < 224 || total > 288 {
+			t.Errorf("%v: %d of 512 lit", mode, total)
+		}
+		if mode != DitherNone && (quarters[0] == 0 || quarters[3] == 128) {
+			t.Errorf("%v: no dithering at the ends, lit per quarter %v", mode, quarters)
+		}
+	}
+}
+
+func TestDitherPatterns(t *testing.T) {
+	cases := []struct {
+		mode DitherMode
+		want []string
+	}{
+		{DitherFloydSteinberg, []string{"#.#.#.#.", ".#.#.#.#"}},
+		{DitherAtkinson, []string{"#..##..#", ".##..##."}}, // Only 6/8 of the error moves on
+		{DitherBayer, []string{"#.#.#.#.", ".#.#.#.#"}},
+	}
+	for _, c := range cases {
+		got := monoRows(Dither(uniformGray(8, 2, 128), ImageOptions{Dither: c.mode}))
+		if strings.Join(got, "\n") != strings.Join(c.want, "\n") {
+			t.Errorf("%v at gray 128:\n%s", c.mode, strings.Join(got, "\n"))
+		}
+	}
+
+	// Bayer lights exactly the cells below the level
+	m := Dither(uniformGray(8, 8, 128), ImageOptions{Dither: DitherBayer})
+	for y := 0; y < 8; y++ {
+		for x := 0; x < 8; x++ {
+			if m.Bit(x, y) != (bayer8[y][x] < 32) {
+				t.Errorf("bayer cell (%d,%d) = %d lit %v", x, y, bayer8[y][x], m.Bit(x, y))
+			}
+		}
+	}
+}
+
+func TestDitherLevels(t *testing.T) {
+	transparent := image.NewNRGBA(image.Rect(0, 0, 1, 1))
+	transparent.SetNRGBA(0, 0, color.NRGBA{R: 0xff, G: 0xff, B: 0xff})
+	cases := []struct {
+		name string
+		img  image.Image
+		opts ImageOptions
+		lit  bool
+	}{
+		{"below default threshold", uniformGray(1, 1, 127), ImageOptions{}, false},
+		{"at default threshold", uniformGray(1, 1, 128), ImageOptions{}, true},
+		{"at threshold", uniformGray(1, 1, 100), ImageOptions{Threshold: 100}, true},
+		{"below threshold", uniformGray(1, 1, 99), ImageOptions{Threshold: 100}, false},
+		{"inverted bright", uniformGray(1, 1, 200), ImageOptions{Invert: true}, false},
+		{"inverted dark", uniformGray(1, 1, 50), ImageOptions{Invert: true}, true},
+		{"gamma brightens", uniformGray(1, 1, 100), ImageOptions{Gamma: 2}, true},
+		{"gamma darkens", uniformGray(1, 1, 100), ImageOptions{Gamma: 0.5, Threshold: 50}, false},
+		{"gamma 1 unchanged", uniformGray(1, 1, 100), ImageOptions{Gamma: 1, Threshold: 100}, true},
+		{"transparent is black", transparent, ImageOptions{}, false},
+	}
+	for _, c := range cases {
+		if got := Dither(c.img, c.opts).Bit(0, 0); got != c.lit {
+			t.Errorf("%s: lit %v, want %v", c.name, got, c.lit)
+		}
+	}
+}
@@ -0,0 +1,114 @@
+package nanohatoled
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"io"
+
+	"github.com/disintegration/imaging"
+)
+
+// ScaleMode - How DrawImage fits a picture into its box
+type ScaleMode int
+
+const (
+	ScaleFit     ScaleMode = iota // Keep aspect ratio, whole picture visible, centered
+	ScaleFill                     // Keep aspect ratio, cover the box, crop the overflow
+	ScaleStretch                  // Resize to the box, ignoring aspect ratio
+	ScaleCenter                   // Keep original size, centered, cropped by the box
+)
+
+// scaleToBox - Resize img for a w x h box, upscaling with nearest neighbour to keep pixel art sharp
+func scaleToBox(img image.Image, w, h int, mode ScaleMode) image.Image {
+	b := img.Bounds()
+	filter := imaging.Lanczos
+	if b.Dx() <= w && b.Dy() <= h {
+		filter = imaging.NearestNeighbor
+	}
+	switch mode {
+	case ScaleFill:
+		return imaging.Fill(img, w, h, imaging.Center, filter)
+	case ScaleStretch:
+		return imaging.Resize(img, w, h, filter)
+	case ScaleCenter:
+		return img
+	}
+	// imaging.Fit only ever shrinks, pictures smaller than the box have to grow too
+	fw, fh := w, b.Dy()*w/b.Dx()
+	if fh > h {
+		fw, fh = b.Dx()*h/b.Dy(), h
+	}
+	if fw < 1 {
+		fw = 1
+	}
+	if fh < 1 {
+		fh = 1
+	}
+	if fw == b.Dx() && fh == b.Dy() {
+		return img
+	}
+	return imaging.Resize(img, fw, fh, filter)
+}
+
+// DrawImage - Draw img into the box at (x, y) of size w x h, a zero or negative size uses
+// the picture's own size. Conversion and scaling follow opts. With a mask only pixels where
+// the mask is at least half opaque are drawn, the rest keeps the background. The mask covers
+// the whole picture: one of another size is stretched over it before both are scaled.
+func (canvas *Canvas) DrawImage(x int, y int, w int, h int, img image.Image, opts ImageOptions) {
+	if w <= 0 || h <= 0 {
+		w, h = img.Bounds().Dx(), img.Bounds().Dy()
+	}
+	if opts.UseAlpha && opts.Mask == nil {
+		opts.Mask = img
+	}
+
+	mono := Dither(scaleToBox(img, w, h, opts.Scale), opts)
+	var mask image.Image
+	if opts.Mask != nil {
+		mask = opts.Mask
+		if size := img.Bounds().Size(); mask.Bounds().Size() != size {
+			mask = imaging.Resize(mask, size.X, size.Y, imaging.Linear)
+		}
+		mask = scaleToBox(mask, w, h, opts.Scale)
+	}
+
+	// Center the converted picture in the box and crop what sticks out
+	mw, mh := mono.Rect.Dx(), mono.Rect.Dy()
+	offX, offY := (w-mw)/2, (h-mh)/2
+	for py := 0; py < h; py++ {
+		sy := py - offY
+		if sy < 0 || sy >= mh {
+			continue
+		}
+		for px := 0; px < w; px++ {
+			sx := px - offX
+			if sx < 0 || sx >= mw {
+				continue
+			}
+			if mask != nil {
+				mb := mask.Bounds()
+				_, _, _, a := mask.At(mb.Min.X+sx, mb.Min.Y+sy).RGBA()
+				if a < 0x8000 {
+					continue
+				}
+			}
+			canvas.set(x+px, y+py, mono.Bit(sx, sy))
+		}
+	}
+}
+
+// DrawImageReader - Decode a picture (PNG, JPEG, GIF, BMP, TIFF) from r and draw it like DrawImage
+func (canvas *Canvas) DrawImageReader(x int, y int, w int, h int, r io.Reader, opts ImageOptions) error {
+	img, err := imaging.Decode(r)
+	if err != nil {
+		return fmt.Errorf("decode image failed: %w", err)
+	}
+	canvas.DrawImage(x, y, w, h, img, opts)
+	return nil
+}
+
+// DrawImageBytes - Draw an encoded picture held in memory (e.g. from go:embed) like DrawImageReader
+func (canvas *Canvas) DrawImageBytes(x int, y int, w int, h int, data []byte, opts ImageOptions) error {
+	return canvas.DrawImageReader(x, y, w, h, bytes.NewReader(data), opts)
+}
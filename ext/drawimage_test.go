@@ -0,0 +1,51 @@
+package nanohatoled
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+func TestDrawImageMaskSize(t *testing.T) {
+	img := image.NewGray(image.Rect(0, 0, 4, 2))
+	for i := range img.Pix {
+		img.Pix[i] = 0xff
+	}
+	// Half the size of the picture, left half opaque
+	mask := image.NewAlpha(image.Rect(0, 0, 2, 1))
+	mask.SetAlpha(0, 0, color.Alpha{A: 0xff})
+
+	oled, _ := testOled(t, SSD1306)
+	for _, mode := range []ScaleMode{ScaleFit, ScaleFill, ScaleStretch, ScaleCenter} {
+		canvas := oled.NewCanvas(8, 6)
+		canvas.DrawImage(0, 0, 8, 6, img, ImageOptions{Mask: mask, Scale: mode})
+		var got []image.Point
+		for y := 0; y < 6; y++ {
+			for x := 0; x < 8; x++ {
+				if canvas.get(x, y) {
+					got = append(got, image.Pt(x, y))
+				}
+			}
+		}
+
+		// Stretch scales the picture to 8x6, fill to 12x6 cropped to the middle 8 columns,
+		// fit to 8x4 at (0,1), center leaves it 4x2 at (2,2)
+		want := image.Rect(0, 0, 4, 6)
+		switch mode {
+		case ScaleFit:
+			want = image.Rect(0, 1, 4, 5)
+		case ScaleCenter:
+			want = image.Rect(2, 2, 4, 4)
+		}
+		if len(got) != want.Dx()*want.Dy() {
+			t.Errorf("mode %d: lit %v, want %v", mode, got, want)
+			continue
+		}
+		for _, p := range got {
+			if !p.In(want) {
+				t.Errorf("mode %d: lit %v, want %v", mode, got, want)
+				break
+			}
+		}
+	}
+}
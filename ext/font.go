@@ -0,0 +1,197 @@
+package nanohatoled
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"image"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/golang/freetype/truetype"
+	"golang.org/x/image/font"
+	"golang.org/x/image/math/fixed"
+)
+
+// Font - Typeface for SetFont: a scalable TrueType font, a fixed-size BitmapFont or a Fallback chain
+type Font interface {
+	face(size float64, bold bool) font.Face
+	hasGlyph(r rune) bool
+}
+
+// trueTypeFont - TrueType font scaled by SetFontSize
+type trueTypeFont struct {
+	f *truetype.Font
+}
+
+func (t trueTypeFont) face(size float64, bold bool) font.Face {
+	return truetype.NewFace(t.f, &truetype.Options{
+		Size:    size,
+		DPI:     FixedDPI,
+		Hinting: font.HintingFull, // Full hinting for clear font edges
+	})
+}
+
+func (t trueTypeFont) hasGlyph(r rune) bool {
+	return t.f.Index(r) != 0
+}
+
+// TrueType - Use an already parsed TrueType font with SetFont
+func TrueType(f *truetype.Font) Font {
+	return trueTypeFont{f}
+}
+
+// LoadFont - Load a TrueType (.ttf), BDF (.bdf) or PCF (.pcf) font; gzipped files are accepted
+func LoadFont(path string) (Font, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("load font failed: %w", err)
+	}
+	if bytes.HasPrefix(data, []byte{0x1f, 0x8b}) {
+		zr, err := gzip.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return nil, fmt.Errorf("load font failed: %w", err)
+		}
+		data, err = io.ReadAll(zr)
+		if err != nil {
+			return nil, fmt.Errorf("load font failed: %w", err)
+		}
+	}
+
+	switch {
+	case bytes.HasPrefix(data, []byte(pcfMagic)):
+		return ParsePCF(data)
+	case bytes.HasPrefix(data, []byte("STARTFONT")):
+		return ParseBDF(bytes.NewReader(data))
+	}
+	f, err := truetype.Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return trueTypeFont{f}, nil
+}
+
+// loadFontDir - Load every font file in dir in name order, a missing dir yields none.
+// Files that fail to load are reported to log and skipped, the others still make the chain.
+func loadFontDir(dir string, log Logger) []Font {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			logf(log, "Read font dir failed: %v", err)
+		}
+		return nil
+	}
+	var fonts []Font
+	for _, e := range entries {
+		ext := strings.ToLower(strings.TrimSuffix(e.Name(), ".gz"))
+		switch filepath.Ext(ext) {
+		case ".ttf", ".bdf", ".pcf":
+		default:
+			continue // READMEs, licenses
+		}
+		f, err := LoadFont(filepath.Join(dir, e.Name()))
+		if err != nil {
+			logf(log, "Skipping fallback font %s: %v", e.Name(), err)
+			continue
+		}
+		fonts = append(fonts, f)
+	}
+	return fonts
+}
+
+// fallbackFont - Ordered font chain, see Fallback
+type fallbackFont struct {
+	fonts []Font
+}
+
+// Fallback - Font drawing each rune with the first of fonts that has a glyph for it.
+// Line metrics come from the first font; runes no font has use its missing glyph.
+// Without fonts it returns nil, which SetFont takes as the built-in fonts.
+func Fallback(fonts ...Font) Font {
+	if len(fonts) == 0 {
+		return nil
+	}
+	return &fallbackFont{fonts: fonts}
+}
+
+func (chain *fallbackFont) face(size float64, bold bool) font.Face {
+	return &fallbackFace{chain: chain.fonts, faces: make([]font.Face, len(chain.fonts)), size: size, bold: bold}
+}
+
+func (chain *fallbackFont) hasGlyph(r rune) bool {
+	for _, f := range chain.fonts {
+		if f.hasGlyph(r) {
+			return true
+		}
+	}
+	return false
+}
+
+// fallbackFace - font.Face creating the faces of a chain on first use
+type fallbackFace struct {
+	chain []Font
+	faces []font.Face
+	size  float64
+	bold  bool
+}
+
+// pick - Face for r, the first font's face when no font has r
+func (ff *fallbackFace) pick(r rune) font.Face {
+	i := 0
+	for j, f := range ff.chain {
+		if f.hasGlyph(r) {
+			i = j
+			break
+		}
+	}
+	if ff.faces[i] == nil {
+		ff.faces[i] = ff.chain[i].face(ff.size, ff.bold)
+	}
+	return ff.faces[i]
+}
+
+func (ff *fallbackFace) Close() error {
+	for _, face := range ff.faces {
+		if face != nil {
+			face.Close()
+		}
+	}
+	return nil
+}
+
+func (ff *fallbackFace) Glyph(dot fixed.Point26_6, r rune) (
+	dr image.Rectangle, mask image.Image, maskp image.Point, advance fixed.Int26_6, ok bool) {
+	return ff.pick(r).Glyph(dot, r)
+}
+
+func (ff *fallbackFace) GlyphBounds(r rune) (bounds fixed.Rectangle26_6, advance fixed.Int26_6, ok bool) {
+	return ff.pick(r).GlyphBounds(r)
+}
+
+func (ff *fallbackFace) GlyphAdvance(r rune) (advance fixed.Int26_6, ok bool) {
+	return ff.pick(r).GlyphAdvance(r)
+}
+
+func (ff *fallbackFace) Kern(r0, r1 rune) fixed.Int26_6 {
+	face := ff.pick(r0)
+	if face != ff.pick(r1) {
+		return 0 // No kerning across fonts
+	}
+	return face.Kern(r0, r1)
+}
+
+func (ff *fallbackFace) Metrics() font.Metrics {
+	if ff.faces[0] == nil {
+		ff.faces[0] = ff.chain[0].face(ff.size, ff.bold)
+	}
+	return ff.faces[0].Metrics()
+}
+
+// SetFont - Draw text with f instead of the built-in DejaVu fonts (nil restores them).
+// Bitmap fonts keep their own pixel size and ignore SetFontSize; SetBold overstrikes them.
+// Runes f lacks are still taken from the fallback font directory.
+func (canvas *Canvas) SetFont(f Font) {
+	canvas.font = f
+}
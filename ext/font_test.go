@@ -0,0 +1,55 @@
+package nanohatoled
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// logRecorder - Logger keeping the messages
+type logRecorder struct {
+	mu    sync.Mutex
+	lines []string
+}
+
+func (l *logRecorder) Printf(format string, v ...interface{}) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.lines = append(l.lines, fmt.Sprintf(format, v...))
+}
+
+// messages - Copy of the messages logged so far
+func (l *logRecorder) messages() []string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return append([]string(nil), l.lines...)
+}
+
+func TestFallbackDirSkipsBadFonts(t *testing.T) {
+	dir := t.TempDir()
+	good, err := os.ReadFile(filepath.Join(testFontDir, defaultFontFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	os.WriteFile(filepath.Join(dir, "a-broken.ttf"), []byte("not a font"), 0644)
+	os.WriteFile(filepath.Join(dir, "b-good.ttf"), good, 0644)
+
+	log := &logRecorder{}
+	opts := DefaultOptions()
+	opts.FontDir = testFontDir
+	opts.FallbackFontDir = dir
+	opts.Logger = log
+	oled, err := OpenVirtual(opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(oled.res.fallback) != 1 {
+		t.Errorf("%d fallback fonts, want 1", len(oled.res.fallback))
+	}
+	if len(log.lines) != 1 || !strings.Contains(log.lines[0], "a-broken.ttf") {
+		t.Errorf("log %q", log.lines)
+	}
+}
@@ -0,0 +1,100 @@
+package nanohatoled
+
+import (
+	"image"
+	"image/draw"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/math/fixed"
+)
+
+const (
+	maxCachedFaces  = 16   // Faces kept per display, the set is dropped when full
+	maxCachedGlyphs = 1024 // Glyphs kept per face, the set is dropped when full
+)
+
+// faceKey - Font, size and weight a cached face was built for
+type faceKey struct {
+	font Font
+	size float64
+	bold bool
+}
+
+// glyphKey - Rune and sub-pixel position of the dot it was rasterized at
+type glyphKey struct {
+	r   rune
+	sub fixed.Point26_6
+}
+
+// cachedGlyph - Rasterized glyph, mask placed at the integer dot plus off
+type cachedGlyph struct {
+	mask    *image.Alpha
+	off     image.Point
+	advance fixed.Int26_6
+	ok      bool
+}
+
+// advanceEntry - Memoized GlyphAdvance result
+type advanceEntry struct {
+	advance fixed.Int26_6
+	ok      bool
+}
+
+// cachedFace - font.Face keeping glyph masks and advances of another face
+type cachedFace struct {
+	font.Face
+	glyphs   map[glyphKey]*cachedGlyph
+	advances map[rune]advanceEntry
+}
+
+func newCachedFace(face font.Face) *cachedFace {
+	return &cachedFace{
+		Face:     face,
+		glyphs:   map[glyphKey]*cachedGlyph{},
+		advances: map[rune]advanceEntry{},
+	}
+}
+
+// glyph - Rasterized r for a dot with the given sub-pixel position, from the cache when possible
+func (cf *cachedFace) glyph(dot fixed.Point26_6, r rune) *cachedGlyph {
+	key := glyphKey{r: r, sub: fixed.Point26_6{X: dot.X & 63, Y: dot.Y & 63}}
+	if g, ok := cf.glyphs[key]; ok {
+		return g
+	}
+
+	g := &cachedGlyph{}
+	dr, mask, maskp, advance, ok := cf.Face.Glyph(key.sub, r)
+	if ok {
+		// Faces reuse their mask buffer between calls, keep a copy
+		g.mask = image.NewAlpha(image.Rect(0, 0, dr.Dx(), dr.Dy()))
+		draw.Draw(g.mask, g.mask.Rect, mask, maskp, draw.Src)
+		g.off, g.advance, g.ok = dr.Min, advance, true
+	}
+	if len(cf.glyphs) >= maxCachedGlyphs {
+		cf.glyphs = map[glyphKey]*cachedGlyph{}
+	}
+	cf.glyphs[key] = g
+	return g
+}
+
+func (cf *cachedFace) Glyph(dot fixed.Point26_6, r rune) (
+	dr image.Rectangle, mask image.Image, maskp image.Point, advance fixed.Int26_6, ok bool) {
+	g := cf.glyph(dot, r)
+	if !g.ok {
+		return
+	}
+	min := g.off.Add(image.Pt(dot.X.Floor(), dot.Y.Floor()))
+	return g.mask.Rect.Add(min), g.mask, image.Point{}, g.advance, true
+}
+
+func (cf *cachedFace) GlyphAdvance(r rune) (advance fixed.Int26_6, ok bool) {
+	if a, found := cf.advances[r]; found {
+		return a.advance, a.ok
+	}
+	advance, ok = cf.Face.GlyphAdvance(r)
+	if len(cf.advances) >= maxCachedGlyphs {
+		cf.advances = map[rune]advanceEntry{}
+	}
+	cf.advances[r] = advanceEntry{advance, ok}
+	return advance, ok
+}
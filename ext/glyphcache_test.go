@@ -0,0 +1,17 @@
+package nanohatoled
+
+import "testing"
+
+// BenchmarkTextUncached - BenchmarkText rebuilding the face and its glyphs every time,
+// as text drawing did before faces were cached
+func BenchmarkTextUncached(b *testing.B) {
+	oled, _ := testOled(b, SSD1306)
+	oled.SetFontSize(24)
+	oled.SetBold(true)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		oled.res.faces = nil
+		oled.Text(10, 10, "12:34:56", true)
+	}
+}
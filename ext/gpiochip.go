@@ -0,0 +1,269 @@
+package nanohatoled
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+	"unsafe"
+
+	"golang.org/x/sys/unix"
+)
+
+// Bias - Pull resistor setting requested for the button lines
+type Bias int
+
+const (
+	BiasAsIs     Bias = iota // Keep what the board or device tree configured
+	BiasPullUp               // Internal pull-up, for buttons switching to ground
+	BiasPullDown             // Internal pull-down, for buttons switching to VCC
+	BiasDisabled             // No internal pull resistor
+)
+
+// String - Bias name as used in configuration
+func (b Bias) String() string {
+	switch b {
+	case BiasAsIs:
+		return "as-is"
+	case BiasPullUp:
+		return "pull-up"
+	case BiasPullDown:
+		return "pull-down"
+	case BiasDisabled:
+		return "disabled"
+	}
+	return fmt.Sprintf("bias(%d)", int(b))
+}
+
+// ParseBias - Look up a bias setting by name (case insensitive)
+func ParseBias(name string) (Bias, error) {
+	for _, b := range []Bias{BiasAsIs, BiasPullUp, BiasPullDown, BiasDisabled} {
+		if strings.EqualFold(name, b.String()) {
+			return b, nil
+		}
+	}
+	return BiasAsIs, fmt.Errorf("unknown bias %q", name)
+}
+
+// GPIO character device uAPI v2 (linux/gpio.h, kernel 5.10+)
+const (
+	gpioLinesMax    = 64
+	gpioMaxNameSize = 32
+	gpioLineNumAttr = 10
+
+	gpioLineFlagActiveLow    = 1 << 1
+	gpioLineFlagInput        = 1 << 2
+	gpioLineFlagEdgeRising   = 1 << 4
+	gpioLineFlagEdgeFalling  = 1 << 5
+	gpioLineFlagBiasPullUp   = 1 << 8
+	gpioLineFlagBiasPullDown = 1 << 9
+	gpioLineFlagBiasDisabled = 1 << 10
+
+	gpioGetChipInfoIoctl   = 0x8044B401 // _IOR(0xB4, 0x01, struct gpiochip_info), also in v1
+	gpioGetLineIoctl       = 0xC250B407 // _IOWR(0xB4, 0x07, struct gpio_v2_line_request)
+	gpioLineGetValuesIoctl = 0xC010B40E // _IOWR(0xB4, 0x0E, struct gpio_v2_line_values)
+
+	gpioLineEventSize = 48 // sizeof(struct gpio_v2_line_event)
+)
+
+// lineRetryDelay - Pause after a failed read of the button lines
+const lineRetryDelay = time.Second
+
+type gpioChipInfo struct {
+	name  [gpioMaxNameSize]byte
+	label [gpioMaxNameSize]byte
+	lines uint32
+}
+
+type gpioLineAttribute struct {
+	id      uint32
+	padding uint32
+	value   uint64 // flags, output values or debounce period, depending on id
+}
+
+type gpioLineConfigAttribute struct {
+	attr gpioLineAttribute
+	mask uint64
+}
+
+type gpioLineConfig struct {
+	flags    uint64
+	numAttrs uint32
+	padding  [5]uint32
+	attrs    [gpioLineNumAttr]gpioLineConfigAttribute
+}
+
+type gpioLineRequest struct {
+	offsets         [gpioLinesMax]uint32
+	consumer        [gpioMaxNameSize]byte
+	config          gpioLineConfig
+	numLines        uint32
+	eventBufferSize uint32
+	padding         [5]uint32
+	fd              int32
+}
+
+type gpioLineValues struct {
+	bits uint64
+	mask uint64
+}
+
+// gpioLines - Button lines requested from a GPIO character device, read as pressed = 1
+type gpioLines struct {
+	fd    int
+	count int
+}
+
+// gpioChipPath - Device path for a chip given as "gpiochip0", "0" or a full path
+func gpioChipPath(chip string) string {
+	if strings.HasPrefix(chip, "/") {
+		return chip
+	}
+	if !strings.HasPrefix(chip, "gpiochip") {
+		chip = "gpiochip" + chip
+	}
+	return "/dev/" + chip
+}
+
+// openGPIOLines - Request offsets of chip as inputs reporting both edges
+func openGPIOLines(chip string, offsets []int, activeLow bool, bias Bias) (*gpioLines, error) {
+	if len(offsets) == 0 || len(offsets) > gpioLinesMax {
+		return nil, fmt.Errorf("unsupported number of GPIO lines: %d", len(offsets))
+	}
+	chipFd, err := unix.Open(gpioChipPath(chip), unix.O_RDWR|unix.O_CLOEXEC, 0)
+	if err != nil {
+		return nil, err
+	}
+	defer unix.Close(chipFd)
+
+	// Check offsets against the chip first: kernels without v2 reject the line request
+	// with EINVAL, which then has to mean the interface is missing
+	var info gpioChipInfo
+	if err := gpioIoctl(chipFd, gpioGetChipInfoIoctl, unsafe.Pointer(&info)); err != nil {
+		return nil, fmt.Errorf("read info of %s failed: %w", gpioChipPath(chip), err)
+	}
+	for _, offset := range offsets {
+		if offset < 0 || offset >= int(info.lines) {
+			return nil, fmt.Errorf("%s has no line %d (%d lines)", gpioChipPath(chip), offset, info.lines)
+		}
+	}
+
+	req := gpioLineRequest{numLines: uint32(len(offsets))}
+	for i, offset := range offsets {
+		req.offsets[i] = uint32(offset)
+	}
+	copy(req.consumer[:gpioMaxNameSize-1], "nanohat-oled")
+	req.config.flags = gpioLineFlagInput | gpioLineFlagEdgeRising | gpioLineFlagEdgeFalling
+	if activeLow {
+		req.config.flags |= gpioLineFlagActiveLow
+	}
+	switch bias {
+	case BiasPullUp:
+		req.config.flags |= gpioLineFlagBiasPullUp
+	case BiasPullDown:
+		req.config.flags |= gpioLineFlagBiasPullDown
+	case BiasDisabled:
+		req.config.flags |= gpioLineFlagBiasDisabled
+	}
+	if err := gpioIoctl(chipFd, gpioGetLineIoctl, unsafe.Pointer(&req)); err != nil {
+		return nil, fmt.Errorf("request lines %v of %s failed: %w", offsets, gpioChipPath(chip), err)
+	}
+	return &gpioLines{fd: int(req.fd), count: len(offsets)}, nil
+}
+
+// gpioChipMissing - Whether err means there is no usable character device: the chip
+// does not exist, or the kernel predates the v2 interface and answers its line request
+// with EINVAL (ENOTTY on some older kernels)
+func gpioChipMissing(err error) bool {
+	return os.IsNotExist(err) || errors.Is(err, unix.ENOTTY) || errors.Is(err, unix.EINVAL)
+}
+
+// gpioIoctl - ioctl with a pointer argument
+func gpioIoctl(fd int, req uintptr, arg unsafe.Pointer) error {
+	if _, _, errno := unix.Syscall(unix.SYS_IOCTL, uintptr(fd), req, uintptr(arg)); errno != 0 {
+		return errno
+	}
+	return nil
+}
+
+// values - Current level of every line, bit i for the i-th requested offset
+func (lines *gpioLines) values() (uint64, error) {
+	v := gpioLineValues{mask: 1<<uint(lines.count) - 1}
+	if err := gpioIoctl(lines.fd, gpioLineGetValuesIoctl, unsafe.Pointer(&v)); err != nil {
+		return 0, err
+	}
+	return v.bits, nil
+}
+
+// wait - Wait up to timeout for edge events and discard them, reports whether there were any
+func (lines *gpioLines) wait(timeout time.Duration) (bool, error) {
+	fds := []unix.PollFd{{Fd: int32(lines.fd), Events: unix.POLLIN}}
+	n, err := unix.Poll(fds, int(timeout/time.Millisecond))
+	if err == unix.EINTR {
+		return false, nil
+	}
+	if err != nil || n == 0 {
+		return false, err
+	}
+	var buf [16 * gpioLineEventSize]byte
+	if _, err := unix.Read(lines.fd, buf[:]); err != nil && err != unix.EAGAIN {
+		return false, err
+	}
+	return true, nil
+}
+
+// Close - Release the lines
+func (lines *gpioLines) Close() error {
+	return unix.Close(lines.fd)
+}
+
+// watchLines - Feed the lines as buttons 0, 1, ... from a goroutine, until the manager stops.
+// Read errors are logged and retried every lineRetryDelay.
+func (m *ButtonManager) watchLines(lines *gpioLines) {
+	go func() {
+		var last uint64
+		failing := false
+		fail := func(err error) {
+			if !failing {
+				logf(m.log, "Reading button lines failed, retrying: %v", err)
+				failing = true
+			}
+			select {
+			case <-time.After(lineRetryDelay):
+			case <-m.ctx.Done():
+			}
+		}
+
+		for m.ctx.Err() == nil {
+			bits, err := lines.values()
+			if err != nil {
+				fail(err)
+				continue
+			}
+			if failing {
+				logf(m.log, "Reading button lines recovered")
+				failing = false
+			}
+			for i := 0; i < lines.count; i++ {
+				if bit := uint64(1) << uint(i); bits&bit != last&bit {
+					m.Feed(i, bits&bit != 0)
+				}
+			}
+			last = bits
+
+			// Time out now and then to notice the end of ctx, drop bounces after an edge
+			edge, err := lines.wait(500 * time.Millisecond)
+			if err != nil {
+				fail(err)
+				continue
+			}
+			if edge && m.timing.Debounce > 0 {
+				time.Sleep(m.timing.Debounce)
+				if _, err := lines.wait(0); err != nil {
+					fail(err)
+				}
+			}
+		}
+	}()
+}
@@ -0,0 +1,49 @@
+package nanohatoled
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+func TestWatchLinesLogsErrors(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	log := &logRecorder{}
+	m := NewButtonManager(ctx, DefaultButtonTiming())
+	m.log = log
+	m.watchLines(&gpioLines{fd: -1, count: 3}) // Every ioctl fails with EBADF
+
+	deadline := time.Now().Add(time.Second)
+	for len(log.messages()) == 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	msgs := log.messages()
+	if len(msgs) != 1 || !strings.Contains(msgs[0], "Reading button lines failed") {
+		t.Errorf("log %q", msgs)
+	}
+}
+
+func TestGPIOChipMissing(t *testing.T) {
+	_, notExist := os.Open("/dev/gpiochip-none")
+	for _, tc := range []struct {
+		err  error
+		want bool
+	}{
+		{notExist, true},
+		{fmt.Errorf("request lines [0] of /dev/gpiochip0 failed: %w", unix.EINVAL), true},
+		{fmt.Errorf("request lines [0] of /dev/gpiochip0 failed: %w", unix.ENOTTY), true},
+		{fmt.Errorf("request lines [0] of /dev/gpiochip0 failed: %w", unix.EBUSY), false},
+		{fmt.Errorf("read info of /dev/gpiochip0 failed: %w", unix.EACCES), false},
+		{fmt.Errorf("/dev/gpiochip0 has no line 40 (32 lines)"), false},
+	} {
+		if got := gpioChipMissing(tc.err); got != tc.want {
+			t.Errorf("gpioChipMissing(%v) = %v, want %v", tc.err, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package nanohatoled
+
+import (
+	"fmt"
+
+	"golang.org/x/exp/io/i2c"
+)
+
+// absentBus - Stand-in for a panel that has not answered yet. Writes succeed so drawing
+// and settings carry on, the frame is sent in full once Probe finds the panel.
+type absentBus struct{}
+
+func (absentBus) Write(buf []byte) error { return nil }
+
+func (absentBus) Close() error { return nil }
+
+// openBus - Open the bus of the panel, replaced in tests
+var openBus = openI2C
+
+// openI2C - Open the I2C device at the address in opts
+func openI2C(opts Options) (bus, error) {
+	dev, err := i2c.Open(&i2c.Devfs{Dev: opts.Bus}, int(opts.Address))
+	if err != nil {
+		return nil, fmt.Errorf("open I2C failed: %w", err)
+	}
+	return dev, nil
+}
+
+// PanelMissing - Whether the display was opened with Options.Hotplug and is still
+// waiting for its panel to answer (see Probe)
+func (nanoOled *NanoOled) PanelMissing() bool {
+	b, ok := nanoOled.dev.(*retryBus)
+	if !ok {
+		return false
+	}
+	_, absent := b.bus.(absentBus)
+	return absent
+}
+
+// Probe - Look for a missing panel: open the bus, run the init sequence and send the
+// current frame. Nil when the panel is there, whether it just appeared or was never missing.
+func (nanoOled *NanoOled) Probe() error {
+	if !nanoOled.PanelMissing() {
+		return nil
+	}
+	b := nanoOled.dev.(*retryBus)
+	dev, err := openBus(nanoOled.opts)
+	if err != nil {
+		return err
+	}
+
+	// No retries while probing, a missing panel would only fill the log
+	nanoOled.dev = dev
+	err = nanoOled.init()
+	nanoOled.dev = b
+	if err != nil {
+		dev.Close()
+		return fmt.Errorf("OLED init failed: %w", err)
+	}
+	b.bus = dev
+	b.stats.Consecutive = 0
+	b.logf("Panel found at 0x%02X on %s", nanoOled.opts.Address, nanoOled.opts.Bus)
+	return nanoOled.draw()
+}
@@ -0,0 +1,86 @@
+package nanohatoled
+
+import (
+	"errors"
+	"testing"
+)
+
+// stubOpenBus - Make openBus return the result of open until the test ends
+func stubOpenBus(t *testing.T, open func(Options) (bus, error)) {
+	saved := openBus
+	openBus = open
+	t.Cleanup(func() { openBus = saved })
+}
+
+func hotplugOptions() Options {
+	opts := DefaultOptions()
+	opts.FontDir = testFontDir
+	opts.NoButtons = true
+	opts.Hotplug = true
+	return opts
+}
+
+func TestProbeAttachesPanel(t *testing.T) {
+	var panel *recordBus // Nil while the panel is absent
+	stubOpenBus(t, func(Options) (bus, error) {
+		if panel == nil {
+			return nil, errors.New("no such device")
+		}
+		return panel, nil
+	})
+
+	oled, err := OpenWithOptions(hotplugOptions())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !oled.PanelMissing() {
+		t.Fatal("panel not missing")
+	}
+	oled.New(0)
+	oled.Pixel(5, 5, true)
+	if err := oled.Send(); err != nil {
+		t.Fatal(err)
+	}
+	if err := oled.Probe(); err == nil {
+		t.Fatal("Probe without a panel succeeded")
+	}
+
+	panel = &recordBus{}
+	if err := oled.Probe(); err != nil {
+		t.Fatal(err)
+	}
+	if oled.PanelMissing() {
+		t.Error("panel still missing")
+	}
+	if len(panel.writes) < 2 || panel.writes[0][1] != ssd1306DisplayOff {
+		t.Fatalf("no init sequence, writes % x", panel.writes)
+	}
+	// Whole frame, including the pixel drawn while the panel was missing
+	last := panel.writes[len(panel.writes)-1]
+	if len(last) != len(oled.buf) || last[1+5] != 1<<5 {
+		t.Errorf("last write % x, want the whole frame", last)
+	}
+
+	// Nothing left to do once attached
+	panel.writes = nil
+	if err := oled.Probe(); err != nil || len(panel.writes) != 0 {
+		t.Errorf("second Probe: %v, writes % x", err, panel.writes)
+	}
+}
+
+func TestOpenButtonError(t *testing.T) {
+	stubOpenBus(t, func(Options) (bus, error) { return &recordBus{}, nil })
+	opts := hotplugOptions()
+	opts.NoButtons = false
+	opts.ButtonPins = []string{"K1"} // Not a line offset
+	_, err := OpenWithOptions(opts)
+	var btnErr *ButtonError
+	if !errors.As(err, &btnErr) {
+		t.Fatalf("got %v, want a ButtonError", err)
+	}
+
+	opts.NoButtons = true
+	if _, err := OpenWithOptions(opts); err != nil {
+		t.Error(err)
+	}
+}
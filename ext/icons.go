@@ -0,0 +1,163 @@
+package nanohatoled
+
+import (
+	"fmt"
+	"image"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/disintegration/imaging"
+)
+
+// IconSizes - Sizes (in pixels, square) of the built-in icon set
+var IconSizes = []int{8, 12, 16}
+
+// iconKey - Icon name and height
+type iconKey struct {
+	name string
+	size int
+}
+
+var (
+	builtinOnce sync.Once
+	builtinSet  map[iconKey]*Mono
+)
+
+// parseIcon - Convert rows of '#' and '.' to an image, malformed data is a programming error
+func parseIcon(name string, size int, rows []string) *Mono {
+	if len(rows) != size {
+		panic(fmt.Sprintf("icon %s/%d has %d rows", name, size, len(rows)))
+	}
+	m := NewMono(size, size)
+	for y, row := range rows {
+		if len(row) != size {
+			panic(fmt.Sprintf("icon %s/%d: row %d is %d wide", name, size, y, len(row)))
+		}
+		for x := 0; x < size; x++ {
+			m.SetBit(x, y, row[x] == '#')
+		}
+	}
+	return m
+}
+
+// transformIcon - Copy of src where pixel (x, y) is taken from src at from(x, y)
+func transformIcon(src *Mono, from func(x, y int) (int, int)) *Mono {
+	w, h := src.Rect.Dx(), src.Rect.Dy()
+	m := NewMono(w, h)
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			m.SetBit(x, y, src.Bit(from(x, y)))
+		}
+	}
+	return m
+}
+
+// builtinIconSet - Parsed built-in icons, built on first use
+func builtinIconSet() map[iconKey]*Mono {
+	builtinOnce.Do(func() {
+		builtinSet = map[iconKey]*Mono{}
+		for name, sizes := range builtinIcons {
+			for size, rows := range sizes {
+				builtinSet[iconKey{name, size}] = parseIcon(name, size, rows)
+			}
+		}
+		for _, size := range IconSizes {
+			up, n := builtinSet[iconKey{"arrow-up", size}], size-1
+			builtinSet[iconKey{"arrow-down", size}] = transformIcon(up, func(x, y int) (int, int) { return x, n - y })
+			builtinSet[iconKey{"arrow-right", size}] = transformIcon(up, func(x, y int) (int, int) { return y, n - x })
+			builtinSet[iconKey{"arrow-left", size}] = transformIcon(up, func(x, y int) (int, int) { return n - y, x })
+		}
+	})
+	return builtinSet
+}
+
+// IconNames - Names of the built-in icons, each available in all IconSizes
+func IconNames() []string {
+	var names []string
+	for key := range builtinIconSet() {
+		if key.size == IconSizes[0] {
+			names = append(names, key.name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// iconFromImage - Lit where img is opaque if it has transparency, else where it is bright
+func iconFromImage(img image.Image) *Mono {
+	b := img.Bounds()
+	translucent := false
+	for y := b.Min.Y; y < b.Max.Y && !translucent; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			if _, _, _, a := img.At(x, y).RGBA(); a < 0xffff {
+				translucent = true
+				break
+			}
+		}
+	}
+
+	m := NewMono(b.Dx(), b.Dy())
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			r, g, bl, a := img.At(x, y).RGBA()
+			on := (r+g+bl)/3 > 0x7fff
+			if translucent {
+				on = a >= 0x8000
+			}
+			m.SetBit(x-b.Min.X, y-b.Min.Y, on)
+		}
+	}
+	return m
+}
+
+// AddIcon - Register img as icon name at its own height, replacing a built-in of that size.
+// Pictures with transparency are lit where opaque, others where bright.
+func (nanoOled *NanoOled) AddIcon(name string, img image.Image) {
+	if nanoOled.res.icons == nil {
+		nanoOled.res.icons = map[iconKey]*Mono{}
+	}
+	nanoOled.res.icons[iconKey{name, img.Bounds().Dy()}] = iconFromImage(img)
+}
+
+// LoadIcons - Add every PNG in dir as an icon named after the file without its extension,
+// at the picture's height. A missing dir is ignored, files that fail to load are reported
+// to Options.Logger and skipped.
+func (nanoOled *NanoOled) LoadIcons(dir string) error {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.png"))
+	if err != nil {
+		return err
+	}
+	for _, path := range paths {
+		name := filepath.Base(path)
+		img, err := imaging.Open(path)
+		if err != nil {
+			logf(nanoOled.opts.Logger, "Skipping icon %s: %v", name, err)
+			continue
+		}
+		nanoOled.AddIcon(strings.TrimSuffix(name, filepath.Ext(name)), img)
+	}
+	return nil
+}
+
+// DrawIcon - Draw icon name of the given size with its top-left corner at (x, y).
+// Only the icon's lit pixels are drawn, in iconColor; the background is left as is.
+func (canvas *Canvas) DrawIcon(x int, y int, name string, size int, iconColor bool) error {
+	key := iconKey{name, size}
+	icon, ok := canvas.res.icons[key]
+	if !ok {
+		icon, ok = builtinIconSet()[key]
+	}
+	if !ok {
+		return fmt.Errorf("unknown icon %q at size %d", name, size)
+	}
+	for iy := 0; iy < icon.Rect.Dy(); iy++ {
+		for ix := 0; ix < icon.Rect.Dx(); ix++ {
+			if icon.Bit(ix, iy) {
+				canvas.set(x+ix, y+iy, iconColor)
+			}
+		}
+	}
+	return nil
+}
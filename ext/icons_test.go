@@ -0,0 +1,35 @@
+package nanohatoled
+
+import (
+	"image/png"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoadIconsSkipsBadFiles(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "broken.png"), []byte("not a picture"), 0644)
+	oled, _ := testOled(t, SSD1306)
+	icon := oled.NewCanvas(4, 4)
+	icon.Fill(true)
+	f, err := os.Create(filepath.Join(dir, "wifi.v2.png"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	png.Encode(f, icon.Mono())
+	f.Close()
+
+	log := &logRecorder{}
+	oled.opts.Logger = log
+	if err := oled.LoadIcons(dir); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := oled.res.icons[iconKey{"wifi.v2", 4}]; !ok {
+		t.Errorf("icons %v, want wifi.v2", oled.res.icons)
+	}
+	if len(log.lines) != 1 || !strings.Contains(log.lines[0], "broken.png") {
+		t.Errorf("log %q", log.lines)
+	}
+}
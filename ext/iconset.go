@@ -0,0 +1,666 @@
+package nanohatoled
+
+// builtinIcons - Embedded icon set by name and size, '#' marks a lit pixel.
+// arrow-down, arrow-left and arrow-right are rotated from arrow-up (see builtinIconSet).
+var builtinIcons = map[string]map[int][]string{
+	"ethernet": {
+		8: {
+			"########",
+			"#......#",
+			"#.####.#",
+			"#......#",
+			"#......#",
+			"##....##",
+			".#....#.",
+			".######.",
+		},
+		12: {
+			"............",
+			".##########.",
+			".#........#.",
+			".#.#.##.#.#.",
+			".#........#.",
+			".#........#.",
+			".#........#.",
+			".###....###.",
+			"...#....#...",
+			"...######...",
+			"............",
+			"............",
+		},
+		16: {
+			"................",
+			".##############.",
+			".#............#.",
+			".#..#.#..#.#..#.",
+			".#..#.#..#.#..#.",
+			".#............#.",
+			".#............#.",
+			".#............#.",
+			".#............#.",
+			".#............#.",
+			".####......####.",
+			"....#......#....",
+			"....#......#....",
+			"....########....",
+			"................",
+			"................",
+		},
+	},
+	"wifi-0": {
+		8: {
+			"........",
+			"........",
+			"........",
+			"........",
+			"........",
+			"........",
+			"........",
+			"...##...",
+		},
+		12: {
+			"............",
+			"............",
+			"............",
+			"............",
+			"............",
+			"............",
+			"............",
+			"............",
+			"............",
+			"............",
+			".....##.....",
+			".....##.....",
+		},
+		16: {
+			"................",
+			"................",
+			"................",
+			"................",
+			"................",
+			"................",
+			"................",
+			"................",
+			"................",
+			"................",
+			"................",
+			"................",
+			"................",
+			".......##.......",
+			".......##.......",
+			"................",
+		},
+	},
+	"wifi-1": {
+		8: {
+			"........",
+			"........",
+			"........",
+			"........",
+			"...##...",
+			"..#..#..",
+			"........",
+			"...##...",
+		},
+		12: {
+			"............",
+			"............",
+			"............",
+			"............",
+			"............",
+			"............",
+			"............",
+			".....##.....",
+			"....#..#....",
+			"............",
+			".....##.....",
+			".....##.....",
+		},
+		16: {
+			"................",
+			"................",
+			"................",
+			"................",
+			"................",
+			"................",
+			"................",
+			"................",
+			"................",
+			"................",
+			".......##.......",
+			"......#..#......",
+			"................",
+			".......##.......",
+			".......##.......",
+			"................",
+		},
+	},
+	"wifi-2": {
+		8: {
+			"........",
+			"........",
+			"..####..",
+			".#....#.",
+			"...##...",
+			"..#..#..",
+			"........",
+			"...##...",
+		},
+		12: {
+			"............",
+			"............",
+			"............",
+			"............",
+			"....####....",
+			"..##....##..",
+			".#........#.",
+			".....##.....",
+			"....#..#....",
+			"............",
+			".....##.....",
+			".....##.....",
+		},
+		16: {
+			"................",
+			"................",
+			"................",
+			"................",
+			"................",
+			"................",
+			"......####......",
+			"....##....##....",
+			"..##........##..",
+			"................",
+			".......##.......",
+			"......#..#......",
+			"................",
+			".......##.......",
+			".......##.......",
+			"................",
+		},
+	},
+	"wifi-3": {
+		8: {
+			".######.",
+			"#......#",
+			"..####..",
+			".#....#.",
+			"...##...",
+			"..#..#..",
+			"........",
+			"...##...",
+		},
+		12: {
+			"............",
+			"...######...",
+			".##......##.",
+			"#..........#",
+			"....####....",
+			"..##....##..",
+			".#........#.",
+			".....##.....",
+			"....#..#....",
+			"............",
+			".....##.....",
+			".....##.....",
+		},
+		16: {
+			"................",
+			".....######.....",
+			"...##......##...",
+			".##..........##.",
+			"#..............#",
+			"................",
+			"......####......",
+			"....##....##....",
+			"..##........##..",
+			"................",
+			".......##.......",
+			"......#..#......",
+			"................",
+			".......##.......",
+			".......##.......",
+			"................",
+		},
+	},
+	"thermometer": {
+		8: {
+			"...##...",
+			"..#..#..",
+			"..#..#..",
+			"..#..#..",
+			"..####..",
+			".######.",
+			".######.",
+			"..####..",
+		},
+		12: {
+			".....##.....",
+			"....#..#....",
+			"....#..#....",
+			"....#..#....",
+			"....#..#....",
+			"....####....",
+			"....####....",
+			"...######...",
+			"..########..",
+			"..########..",
+			"...######...",
+			"............",
+		},
+		16: {
+			".......##.......",
+			"......#..#......",
+			"......#..#......",
+			"......#..#......",
+			"......#..#......",
+			"......#..#......",
+			"......#..#......",
+			"......####......",
+			"......####......",
+			".....######.....",
+			"....########....",
+			"...##########...",
+			"...##########...",
+			"....########....",
+			".....######.....",
+			"................",
+		},
+	},
+	"cpu": {
+		8: {
+			"..#..#..",
+			".######.",
+			"##....##",
+			".#.##.#.",
+			".#.##.#.",
+			"##....##",
+			".######.",
+			"..#..#..",
+		},
+		12: {
+			"...#.##.#...",
+			"...#.##.#...",
+			"..########..",
+			"###......###",
+			"..#.####.#..",
+			"###.####.###",
+			"###.####.###",
+			"..#.####.#..",
+			"###......###",
+			"..########..",
+			"...#.##.#...",
+			"...#.##.#...",
+		},
+		16: {
+			"................",
+			"....#.#..#.#....",
+			"....#.#..#.#....",
+			"..############..",
+			".##..........##.",
+			"..#..........#..",
+			".##..######..##.",
+			"..#..######..#..",
+			"..#..######..#..",
+			".##..######..##.",
+			"..#..........#..",
+			".##..........##.",
+			"..############..",
+			"....#.#..#.#....",
+			"....#.#..#.#....",
+			"................",
+		},
+	},
+	"memory": {
+		8: {
+			"........",
+			"########",
+			"#.#..#.#",
+			"#.#..#.#",
+			"#......#",
+			"########",
+			"#.#..#.#",
+			"........",
+		},
+		12: {
+			"............",
+			"............",
+			"............",
+			"############",
+			"#..........#",
+			"#.##.##.##.#",
+			"#.##.##.##.#",
+			"#..........#",
+			"############",
+			"#.#.#..#.#.#",
+			"............",
+			"............",
+		},
+		16: {
+			"................",
+			"................",
+			"................",
+			"################",
+			"#..............#",
+			"#.##.##..##.##.#",
+			"#.##.##..##.##.#",
+			"#.##.##..##.##.#",
+			"#..............#",
+			"################",
+			"#.#.#.#..#.#.#.#",
+			"#.#.#.#..#.#.#.#",
+			"................",
+			"................",
+			"................",
+			"................",
+		},
+	},
+	"disk": {
+		8: {
+			"........",
+			"########",
+			"#......#",
+			"#......#",
+			"########",
+			"#....#.#",
+			"########",
+			"........",
+		},
+		12: {
+			"............",
+			"............",
+			"............",
+			".##########.",
+			"#..........#",
+			"#..........#",
+			"############",
+			"#......#.#.#",
+			"#..........#",
+			".##########.",
+			"............",
+			"............",
+		},
+		16: {
+			"................",
+			"................",
+			"................",
+			"................",
+			"..############..",
+			".#............#.",
+			"#..............#",
+			"#..............#",
+			"################",
+			"#..............#",
+			"#........##.##.#",
+			"#..............#",
+			".##############.",
+			"................",
+			"................",
+			"................",
+		},
+	},
+	"clock": {
+		8: {
+			"..####..",
+			".#....#.",
+			"#..#...#",
+			"#..#...#",
+			"#..###.#",
+			"#......#",
+			".#....#.",
+			"..####..",
+		},
+		12: {
+			"...######...",
+			"..#......#..",
+			".#........#.",
+			"#....#.....#",
+			"#....#.....#",
+			"#....#.....#",
+			"#....####..#",
+			"#..........#",
+			"#..........#",
+			".#........#.",
+			"..#......#..",
+			"...######...",
+		},
+		16: {
+			".....######.....",
+			"...##......##...",
+			"..#..........#..",
+			".#............#.",
+			".#......#.....#.",
+			"#.......#......#",
+			"#.......#......#",
+			"#.......#......#",
+			"#.......#####..#",
+			"#..............#",
+			"#..............#",
+			".#............#.",
+			".#............#.",
+			"..#..........#..",
+			"...##......##...",
+			".....######.....",
+		},
+	},
+	"power": {
+		8: {
+			"...##...",
+			".#.##.#.",
+			"#..##..#",
+			"#..##..#",
+			"#......#",
+			"#......#",
+			".#....#.",
+			"..####..",
+		},
+		12: {
+			".....##.....",
+			".....##.....",
+			"..#..##..#..",
+			".#...##...#.",
+			"#....##....#",
+			"#....##....#",
+			"#..........#",
+			"#..........#",
+			".#........#.",
+			"..#......#..",
+			"...######...",
+			"............",
+		},
+		16: {
+			".......##.......",
+			".......##.......",
+			"...#...##...#...",
+			"..#....##....#..",
+			".#.....##.....#.",
+			"#......##......#",
+			"#......##......#",
+			"#......##......#",
+			"#..............#",
+			"#..............#",
+			"#..............#",
+			".#............#.",
+			"..#..........#..",
+			"...##......##...",
+			".....######.....",
+			"................",
+		},
+	},
+	"warning": {
+		8: {
+			"...##...",
+			"..####..",
+			"..#..#..",
+			".##..##.",
+			".######.",
+			"###..###",
+			"########",
+			"........",
+		},
+		12: {
+			".....##.....",
+			".....##.....",
+			"....####....",
+			"....#..#....",
+			"...##..##...",
+			"...##..##...",
+			"..###..###..",
+			"..###..###..",
+			".##########.",
+			".####..####.",
+			"############",
+			"............",
+		},
+		16: {
+			".......##.......",
+			"......####......",
+			"......####......",
+			".....######.....",
+			".....##..##.....",
+			"....###..###....",
+			"....###..###....",
+			"...####..####...",
+			"...####..####...",
+			"..#####..#####..",
+			"..############..",
+			".######..######.",
+			".######..######.",
+			"################",
+			"................",
+			"................",
+		},
+	},
+	"arrow-up": {
+		8: {
+			"...##...",
+			"..####..",
+			".######.",
+			"########",
+			"...##...",
+			"...##...",
+			"...##...",
+			"...##...",
+		},
+		12: {
+			"............",
+			".....##.....",
+			"....####....",
+			"...######...",
+			"..########..",
+			".##########.",
+			"....####....",
+			"....####....",
+			"....####....",
+			"....####....",
+			"....####....",
+			"............",
+		},
+		16: {
+			"................",
+			".......##.......",
+			"......####......",
+			".....######.....",
+			"....########....",
+			"...##########...",
+			"..############..",
+			".##############.",
+			".....######.....",
+			".....######.....",
+			".....######.....",
+			".....######.....",
+			".....######.....",
+			".....######.....",
+			"................",
+			"................",
+		},
+	},
+	"check": {
+		8: {
+			"........",
+			".......#",
+			"......#.",
+			".....#..",
+			"#...#...",
+			".#.#....",
+			"..#.....",
+			"........",
+		},
+		12: {
+			"............",
+			"............",
+			"..........##",
+			".........##.",
+			"........##..",
+			".##....##...",
+			"..##..##....",
+			"...####.....",
+			"....##......",
+			"............",
+			"............",
+			"............",
+		},
+		16: {
+			"................",
+			"................",
+			"................",
+			".............##.",
+			"............###.",
+			"...........###..",
+			"..........###...",
+			".##......###....",
+			".###....###.....",
+			"..###..###......",
+			"...######.......",
+			"....####........",
+			".....##.........",
+			"................",
+			"................",
+			"................",
+		},
+	},
+	"cross": {
+		8: {
+			"#......#",
+			".#....#.",
+			"..#..#..",
+			"...##...",
+			"...##...",
+			"..#..#..",
+			".#....#.",
+			"#......#",
+		},
+		12: {
+			"............",
+			".##......##.",
+			".###....###.",
+			"..###..###..",
+			"...######...",
+			"....####....",
+			"....####....",
+			"...######...",
+			"..###..###..",
+			".###....###.",
+			".##......##.",
+			"............",
+		},
+		16: {
+			"................",
+			"................",
+			"..##........##..",
+			"..###......###..",
+			"...###....###...",
+			"....###..###....",
+			".....######.....",
+			"......####......",
+			"......####......",
+			".....######.....",
+			"....###..###....",
+			"...###....###...",
+			"..###......###..",
+			"..##........##..",
+			"................",
+			"................",
+		},
+	},
+}
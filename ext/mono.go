@@ -0,0 +1,102 @@
+package nanohatoled
+
+import (
+	"image"
+	"image/color"
+)
+
+// Mono - 1-bit image stored in SSD1306 page layout: each byte holds 8 vertical
+// pixels (LSB on top), pages of Stride bytes follow each other top to bottom.
+// Colors written through Set are lit when their luminance exceeds Threshold.
+type Mono struct {
+	Pix       []byte
+	Stride    int
+	Rect      image.Rectangle
+	Threshold uint16 // Luminance (0-65535) above which a pixel is lit
+}
+
+// monoModel - Converts any color to fully lit or unlit gray
+var monoModel = color.ModelFunc(func(c color.Color) color.Color {
+	r, g, b, _ := c.RGBA()
+	if (r+g+b)/3 > 0x7fff {
+		return color.Gray{Y: 0xff}
+	}
+	return color.Gray{}
+})
+
+// NewMono - Allocate an unlit w x h image, height is rounded up to whole pages internally
+func NewMono(w, h int) *Mono {
+	return &Mono{
+		Pix:       make([]byte, w*((h+7)/8)),
+		Stride:    w,
+		Rect:      image.Rect(0, 0, w, h),
+		Threshold: 0x7fff,
+	}
+}
+
+// ColorModel - Implements image.Image
+func (m *Mono) ColorModel() color.Model { return monoModel }
+
+// Bounds - Implements image.Image
+func (m *Mono) Bounds() image.Rectangle { return m.Rect }
+
+// At - Implements image.Image
+func (m *Mono) At(x, y int) color.Color {
+	if m.Bit(x, y) {
+		return color.Gray{Y: 0xff}
+	}
+	return color.Gray{}
+}
+
+// RGBA64At - Implements image.RGBA64Image, avoids allocations in image/draw
+func (m *Mono) RGBA64At(x, y int) color.RGBA64 {
+	if m.Bit(x, y) {
+		return color.RGBA64{R: 0xffff, G: 0xffff, B: 0xffff, A: 0xffff}
+	}
+	return color.RGBA64{A: 0xffff}
+}
+
+// Set - Implements draw.Image, thresholds c by luminance
+func (m *Mono) Set(x, y int, c color.Color) {
+	r, g, b, _ := c.RGBA()
+	m.SetBit(x, y, (r+g+b)/3 > uint32(m.Threshold))
+}
+
+// SetRGBA64 - Implements draw.RGBA64Image
+func (m *Mono) SetRGBA64(x, y int, c color.RGBA64) {
+	m.SetBit(x, y, (uint32(c.R)+uint32(c.G)+uint32(c.B))/3 > uint32(m.Threshold))
+}
+
+// Bit - Report whether pixel (x, y) is lit, false outside bounds
+func (m *Mono) Bit(x, y int) bool {
+	if !(image.Point{X: x, Y: y}.In(m.Rect)) {
+		return false
+	}
+	x, y = x-m.Rect.Min.X, y-m.Rect.Min.Y
+	return m.Pix[x+(y/8)*m.Stride]&(1<<uint(y&7)) != 0
+}
+
+// SetBit - Light or clear pixel (x, y), ignored outside bounds
+func (m *Mono) SetBit(x, y int, on bool) {
+	if !(image.Point{X: x, Y: y}.In(m.Rect)) {
+		return
+	}
+	x, y = x-m.Rect.Min.X, y-m.Rect.Min.Y
+	i := x + (y/8)*m.Stride
+	if on {
+		m.Pix[i] |= 1 << uint(y&7)
+	} else {
+		m.Pix[i] &^= 1 << uint(y&7)
+	}
+}
+
+// Fill - Set every pixel to on
+func (m *Mono) Fill(on bool) {
+	var v byte
+	if on {
+		v = 0xff
+	}
+	for i := range m.Pix {
+		m.Pix[i] = v
+	}
+}
@@ -0,0 +1,104 @@
+package nanohatoled
+
+import (
+	"image"
+	"testing"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/math/fixed"
+)
+
+// BenchmarkSend - Pack and write a full 128x64 frame
+func BenchmarkSend(b *testing.B) {
+	oled, _ := testOled(b, SSD1306)
+	oled.dev = virtualBus{}
+	lit := make([]byte, len(oled.image.Pix))
+	for i := range lit {
+		lit[i] = 0xff
+	}
+	dark := make([]byte, len(lit))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		// Alternate lit and dark frames so every byte changes and the whole frame goes out
+		if i%2 == 0 {
+			copy(oled.image.Pix, lit)
+		} else {
+			copy(oled.image.Pix, dark)
+		}
+		if err := oled.Send(); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkText - Redraw the daemon clock (24pt bold) into the frame buffer
+func BenchmarkText(b *testing.B) {
+	oled, _ := testOled(b, SSD1306)
+	oled.SetFontSize(24)
+	oled.SetBold(true)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		oled.Text(10, 10, "12:34:56", true)
+	}
+}
+
+// sendNRGBA - Send as it was before Mono: threshold every NRGBA pixel through
+// At().RGBA() and set its bit in the pixel buffer
+func sendNRGBA(oled *NanoOled, img *image.NRGBA) error {
+	threshold := uint32(oled.getDynamicThreshold())
+	for x := 0; x < oled.w; x++ {
+		for y := 0; y < oled.h; y++ {
+			r, g, b, _ := img.At(x, y).RGBA()
+			i := 1 + x + (y/8)*oled.w
+			if (r+g+b)/3 > threshold {
+				oled.buf[i] |= 1 << uint(y&7)
+			} else {
+				oled.buf[i] &^= 1 << uint(y&7)
+			}
+		}
+	}
+	return oled.draw()
+}
+
+// BenchmarkSendNRGBA - BenchmarkSend through the NRGBA conversion Send used before Mono
+func BenchmarkSendNRGBA(b *testing.B) {
+	oled, _ := testOled(b, SSD1306)
+	oled.dev = virtualBus{}
+	img := image.NewNRGBA(image.Rect(0, 0, oled.w, oled.h))
+	lit := make([]byte, len(img.Pix))
+	for i := range lit {
+		lit[i] = 0xff
+	}
+	dark := make([]byte, len(lit))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if i%2 == 0 {
+			copy(img.Pix, lit)
+		} else {
+			copy(img.Pix, dark)
+		}
+		if err := sendNRGBA(oled, img); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkTextNRGBA - BenchmarkText compositing the same cached face into an NRGBA
+// frame, as text drawing did before Mono
+func BenchmarkTextNRGBA(b *testing.B) {
+	oled, _ := testOled(b, SSD1306)
+	oled.dev = virtualBus{}
+	oled.SetFontSize(24)
+	oled.SetBold(true)
+	img := image.NewNRGBA(image.Rect(0, 0, oled.w, oled.h))
+	d := &font.Drawer{Dst: img, Src: image.White, Face: oled.face()}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		d.Dot = fixed.P(10, oled.baseline(10))
+		d.DrawString("12:34:56")
+	}
+}
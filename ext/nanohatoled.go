@@ -4,16 +4,12 @@ package nanohatoled
 
 import (
 	"fmt"
-	"image"
-	"image/color"
 	"image/draw"
 	"os"
+	"path/filepath"
 
 	"github.com/disintegration/imaging"
-	"github.com/golang/freetype"
 	"github.com/golang/freetype/truetype"
-	"golang.org/x/exp/io/i2c"
-	"golang.org/x/image/font"
 	"periph.io/x/periph/conn/gpio"
 	"periph.io/x/periph/conn/gpio/gpioreg"
 	"periph.io/x/periph/host"
@@ -32,8 +28,11 @@ const (
 	ssd1306VerticalAndLeftHorizontalScroll  = 0x2A
 
 	// Font paths (align with Python version)
-	defaultFontPath    = "/etc/NanoHatOLED/DejaVuSansMono.ttf"
-	defaultBoldFontPath = "/etc/NanoHatOLED/DejaVuSansMono-Bold.ttf"
+	defaultFontDir      = "/etc/NanoHatOLED"
+	defaultFontFile     = "DejaVuSansMono.ttf"
+	defaultBoldFontFile = "DejaVuSansMono-Bold.ttf"
+	defaultFallbackDir  = "fallback" // Under the font dir, fonts for runes DejaVu lacks
+	defaultIconDir      = "icons"    // Under the font dir, user PNG icons
 	FixedDPI           = 72 // Match PIL default DPI for consistent font size
 	
 	// Dynamic threshold base value (adjust with font size)
@@ -42,67 +41,62 @@ const (
 	sizeThresholdLarge  = 24.0 // Large font size threshold
 )
 
+// bus - Write interface shared by the I2C device and the virtual backend
+type bus interface {
+	Write(buf []byte) error
+	Close() error
+}
+
 // NanoOled - OLED controller with font support
 type NanoOled struct {
-	dev *i2c.Device
+	dev  bus
+	ctrl controller // Driver IC command set
 
 	w             int    // Screen width
 	h             int    // Screen height
 	buf           []byte // Pixel buffer
+	sent          []byte // Copy of buf as last written to the panel, nil forces a full update
+	scrolling     bool   // Hardware scroll active
+	contrast      uint8  // Contrast restored after init
+	precharge     int    // Pre-charge register restored after init, -1 keeps the controller default
+	vcomh         int    // VCOMH register restored after init, -1 keeps the controller default
 	rotation      int    // Screen rotation angle
-	rotationState bool   // Rotation applied flag
-	image         *image.NRGBA // Image buffer
+	hwFlipped     bool   // Controller currently mirrors segments and COM scan
+	inverted      bool   // Hardware inversion active
+	off           bool   // Display turned off through Off
 	Btn           [3]gpio.PinIO // GPIO buttons
+	btnActiveLow  bool          // Buttons read low when pressed
+	btnLines      *gpioLines    // Buttons on the GPIO character device, Btn is unused then
+	opts          Options       // Settings opened with, Probe reopens the bus from them
 
-	// Font related fields
-	normalFont  *truetype.Font // Regular monospace font
-	boldFont    *truetype.Font // Bold monospace font
-	currentFont *truetype.Font // Currently used font
-	fontSize    float64        // Current font size
+	Canvas // Root canvas over the 1-bit image buffer (logical orientation)
 }
 
-// init - Initialize SSD1306 OLED controller
-func (nanoOled *NanoOled) init() (err error) {
-	err = nanoOled.dev.Write([]byte{
-		0xae,
-		0x00 | 0x00, // Column offset low
-		0x10 | 0x00, // Column offset high
-		0xd5, 0x80,  // Set display clock divide ratio
-		0xa8, uint8(nanoOled.h - 1), // Set multiplex ratio
-		0xd3, 0x00, // Set display offset
-		0x80 | 0,   // Set segment re-map
-		0x8d, 0x14, // Enable charge pump
-		0x20, 0x0,  // Set memory addressing mode
-		0xA0 | 0x1, // Set COM output scan direction
-		0xC8,       // Set COM pins hardware configuration
-	})
-	if err != nil {
-		return
+// init - Initialize OLED controller, keeping contrast, tuning, inversion and Off from before
+func (nanoOled *NanoOled) init() error {
+	nanoOled.sent = nil // RAM content is undefined after power-up
+	nanoOled.scrolling = false
+	if err := nanoOled.ctrl.init(nanoOled.dev, nanoOled.w, nanoOled.h); err != nil {
+		return err
+	}
+	nanoOled.hwFlipped = false // Orientation is restored by the next draw
+	if nanoOled.contrast != nanoOled.ctrl.defaultContrast(nanoOled.h) {
+		if err := nanoOled.SetContrast(nanoOled.contrast); err != nil {
+			return err
+		}
 	}
-
-	if nanoOled.h == 32 {
-		err = nanoOled.dev.Write([]byte{
-			0xda, 0x02, // Set COM pins configuration
-			0x81, 0x8f, // Set contrast control
-		})
+	if err := nanoOled.restoreTuning(); err != nil {
+		return err
 	}
-
-	if nanoOled.h == 64 {
-		err = nanoOled.dev.Write([]byte{
-			0xda, 0x12, // Set COM pins configuration
-			0x81, 0x7f, // Set contrast control
-		})
+	if nanoOled.inverted {
+		if err := nanoOled.SetInvert(true); err != nil {
+			return err
+		}
 	}
-
-	err = nanoOled.dev.Write([]byte{
-		0x9d, 0xf1, // Set pre-charge period
-		0xdb, 0x40, // Set VCOMH deselect level
-		0xa4,       // Disable entire display on
-		0xa6,       // Set normal display
-		0x2e,       // Deactivate scroll
-		0xaf,       // Turn on display
-	})
-	return
+	if nanoOled.off {
+		return writeCommands(nanoOled.dev, ssd1306DisplayOff)
+	}
+	return nil
 }
 
 // loadFontFile - Load truetype font from file path
@@ -114,37 +108,132 @@ func loadFontFile(path string) (*truetype.Font, error) {
 	return truetype.Parse(fontBytes)
 }
 
-// Open - Initialize OLED and buttons, load fonts
-func Open() (*NanoOled, error) {
-	dev, err := i2c.Open(&i2c.Devfs{Dev: "/dev/i2c-0"}, 0x3C)
+// newNanoOled - Build a controller on top of dev with the geometry and fonts from opts
+func newNanoOled(dev bus, opts Options) (*NanoOled, error) {
+	fontDir := opts.FontDir
+	if fontDir == "" {
+		fontDir = defaultFontDir
+	}
+
+	ctrl, err := newController(opts.Controller)
 	if err != nil {
-		return nil, fmt.Errorf("open I2C failed: %w", err)
+		return nil, err
 	}
 
-	buf := make([]byte, 128*(64/8)+1)
+	buf := make([]byte, opts.Width*(opts.Height/8)+1)
 	buf[0] = 0x40 // Data command prefix
 	oled := &NanoOled{
-		dev:      dev,
-		w:        128,
-		h:        64,
-		buf:      buf,
-		fontSize: 14, // Default font size
-	}
+		dev:       dev,
+		ctrl:      ctrl,
+		contrast:  ctrl.defaultContrast(opts.Height),
+		precharge: -1,
+		vcomh:     -1,
+		w:         opts.Width,
+		h:         opts.Height,
+		buf:       buf,
+		opts:      opts,
+	}
+	res := &resources{}
+	oled.res = res
+	oled.fontSize = 14 // Default font size
 
 	// Load regular and bold fonts
-	oled.normalFont, err = loadFontFile(defaultFontPath)
+	res.normalFont, err = loadFontFile(filepath.Join(fontDir, defaultFontFile))
 	if err != nil {
 		return nil, fmt.Errorf("load regular font failed: %w", err)
 	}
-	oled.boldFont, err = loadFontFile(defaultBoldFontPath)
+	res.boldFont, err = loadFontFile(filepath.Join(fontDir, defaultBoldFontFile))
 	if err != nil {
 		return nil, fmt.Errorf("load bold font failed: %w", err)
 	}
-	oled.currentFont = oled.normalFont
+	oled.currentFont = res.normalFont
+
+	fallbackDir := opts.FallbackFontDir
+	if fallbackDir == "" {
+		fallbackDir = filepath.Join(fontDir, defaultFallbackDir)
+	}
+	res.fallback = loadFontDir(fallbackDir, opts.Logger)
+
+	iconDir := opts.IconDir
+	if iconDir == "" {
+		iconDir = filepath.Join(fontDir, defaultIconDir)
+	}
+	if err := oled.LoadIcons(iconDir); err != nil {
+		return nil, err
+	}
+
+	return oled, nil
+}
+
+// Open - Initialize OLED and buttons with default settings, load fonts
+func Open() (*NanoOled, error) {
+	return OpenWithOptions(DefaultOptions())
+}
+
+// ButtonError - Button setup failed, the display itself could be opened: opening again
+// with Options.NoButtons set works without them
+type ButtonError struct {
+	Err error
+}
+
+func (e *ButtonError) Error() string { return "button setup failed: " + e.Err.Error() }
 
-	// Initialize OLED display
+func (e *ButtonError) Unwrap() error { return e.Err }
+
+// OpenWithOptions - Initialize OLED and buttons as described by opts, load fonts.
+// Errors of the button setup are *ButtonError.
+func OpenWithOptions(opts Options) (*NanoOled, error) {
+	if err := opts.validate(); err != nil {
+		return nil, err
+	}
+
+	dev, err := openBus(opts)
+	if err != nil {
+		if !opts.Hotplug {
+			return nil, err
+		}
+		dev = absentBus{}
+	}
+
+	oled, err := newNanoOled(newRetryBus(dev, opts), opts)
+	if err != nil {
+		dev.Close()
+		return nil, err
+	}
+
+	// Initialize OLED display, or carry on without it until Probe finds one
 	if err := oled.init(); err != nil {
-		return nil, fmt.Errorf("OLED init failed: %w", err)
+		dev.Close()
+		if !opts.Hotplug {
+			return nil, fmt.Errorf("OLED init failed: %w", err)
+		}
+		dev = absentBus{}
+		oled.dev.(*retryBus).bus = dev
+	}
+	if oled.PanelMissing() && opts.Logger != nil {
+		opts.Logger.Printf("No panel at 0x%02X on %s, waiting for one", opts.Address, opts.Bus)
+	}
+
+	if opts.NoButtons {
+		return oled, nil
+	}
+
+	// Prefer the GPIO character device, sysfs GPIO is gone from recent kernels
+	if opts.ButtonChip != "" {
+		offsets, err := opts.buttonLines()
+		if err != nil {
+			dev.Close()
+			return nil, &ButtonError{err}
+		}
+		lines, err := openGPIOLines(opts.ButtonChip, offsets, opts.ButtonActiveLow, opts.ButtonBias)
+		if err == nil {
+			oled.btnLines = lines
+			return oled, nil
+		}
+		if !gpioChipMissing(err) {
+			dev.Close()
+			return nil, &ButtonError{err}
+		}
 	}
 
 	// Initialize host peripherals
@@ -152,15 +241,17 @@ func Open() (*NanoOled, error) {
 		fmt.Printf("Host init warning: %v\n", err)
 	}
 
-	// Initialize GPIO buttons
-	btnPins := []string{"0", "2", "3"}
-	for i, pinName := range btnPins {
+	// Initialize GPIO buttons, both edges so releases are seen too
+	oled.btnActiveLow = opts.ButtonActiveLow
+	for i, pinName := range opts.ButtonPins {
 		pin := gpioreg.ByName(pinName)
 		if pin == nil {
-			return nil, fmt.Errorf("GPIO%s not found", pinName)
+			dev.Close()
+			return nil, &ButtonError{fmt.Errorf("GPIO%s not found", pinName)}
 		}
-		if err := pin.In(gpio.PullNoChange, gpio.RisingEdge); err != nil {
-			return nil, fmt.Errorf("GPIO%s init failed: %w", pinName, err)
+		if err := pin.In(gpio.PullNoChange, gpio.BothEdges); err != nil {
+			dev.Close()
+			return nil, &ButtonError{fmt.Errorf("GPIO%s init failed: %w", pinName, err)}
 		}
 		oled.Btn[i] = pin
 	}
@@ -173,38 +264,69 @@ func OpenBtn() {}
 
 // On - Turn on OLED display
 func (nanoOled *NanoOled) On() error {
-	return nanoOled.dev.Write([]byte{ssd1306DisplayOn})
+	if err := writeCommands(nanoOled.dev, ssd1306DisplayOn); err != nil {
+		return err
+	}
+	nanoOled.off = false
+	return nil
 }
 
-// Off - Turn off OLED display
+// Off - Turn off OLED display (sleep mode, charge pump stopped), kept across re-init.
+// RAM keeps the frame and Send still updates it, On shows it again.
 func (nanoOled *NanoOled) Off() error {
-	return nanoOled.dev.Write([]byte{ssd1306DisplayOff})
+	if err := writeCommands(nanoOled.dev, ssd1306DisplayOff); err != nil {
+		return err
+	}
+	nanoOled.off = true
+	return nil
+}
+
+// IsOn - Whether the display is on, i.e. not put to sleep with Off
+func (nanoOled *NanoOled) IsOn() bool {
+	return !nanoOled.off
 }
 
-// Close - Close I2C connection
+// Close - Close I2C connection and release the button lines
 func (nanoOled *NanoOled) Close() error {
+	if nanoOled.btnLines != nil {
+		nanoOled.btnLines.Close()
+		nanoOled.btnLines = nil
+	}
 	return nanoOled.dev.Close()
 }
 
-// New - Create new image buffer with specified rotation
+// Size - Logical drawing area in pixels (width and height swap at 90/270 rotation)
+func (nanoOled *NanoOled) Size() (int, int) {
+	if nanoOled.rotation == 90 || nanoOled.rotation == 270 {
+		return nanoOled.h, nanoOled.w
+	}
+	return nanoOled.w, nanoOled.h
+}
+
+// newImage - Allocate an empty image buffer matching the logical drawing area
+func (nanoOled *NanoOled) newImage() *Mono {
+	return NewMono(nanoOled.Size())
+}
+
+// New - Create new image buffer with specified rotation (0, 90, 180 or 270 degrees).
+// 180 is done by the controller, 90 and 270 are mapped in Send.
 func (nanoOled *NanoOled) New(rotation int) {
-	nanoOled.rotation = rotation
-	nanoOled.rotationState = false
-	nanoOled.Clear()
-	if rotation == 90 || rotation == 270 {
-		nanoOled.image = image.NewNRGBA(image.Rect(0, 0, 64, 128))
-	} else {
-		nanoOled.image = image.NewNRGBA(image.Rect(0, 0, 128, 64))
+	switch rotation {
+	case 90, 180, 270:
+		nanoOled.rotation = rotation
+	default:
+		nanoOled.rotation = 0
 	}
+	nanoOled.Clear()
 }
 
 // getDynamicThreshold - Get dynamic binarization threshold based on font size
-func (nanoOled *NanoOled) getDynamicThreshold() uint16 {
+func (canvas *Canvas) getDynamicThreshold() uint16 {
 	var thresh uint16
 	switch {
-	case nanoOled.fontSize <= sizeThresholdSmall:
+	case canvas.fontSize <= sizeThresholdSmall:
 		thresh = baseAntiAliasThresh - 10 // Strict threshold for small fonts (preserve arcs)
-	case nanoOled.fontSize >= sizeThresholdLarge:
+	case canvas.fontSize >= sizeThresholdLarge:
 		thresh = baseAntiAliasThresh + 10 // Loose threshold for large fonts (anti-aliasing)
 	default:
 		thresh = baseAntiAliasThresh // Base threshold for medium fonts
@@ -220,151 +342,141 @@ func (nanoOled *NanoOled) Image(imagePath string) error {
 	}
 
 	// Resize image to fit screen
-	img = imaging.Fit(img, 128, 64, imaging.NearestNeighbor)
+	lw, lh := nanoOled.Size()
+	img = imaging.Fit(img, lw, lh, imaging.NearestNeighbor)
 
 	// Convert to grayscale and binarize with dynamic threshold
 	grayImg := imaging.Grayscale(img)
-	binaryImg := image.NewNRGBA(grayImg.Bounds())
-	draw.Draw(binaryImg, binaryImg.Bounds(), image.NewUniform(color.Black), image.Point{}, draw.Src)
+	binaryImg := nanoOled.newImage()
 
 	// Get dynamic threshold (adapt to arc preservation for different fonts)
-	threshold := nanoOled.getDynamicThreshold()
-	
-	for y := 0; y < grayImg.Bounds().Dy(); y++ {
-		for x := 0; x < grayImg.Bounds().Dx(); x++ {
-			r, g, b, _ := grayImg.At(x, y).RGBA()
-			// Normalized grayscale calculation (RGBA range 0-65535)
-			gray := uint16((r + g + b) / 3)
-			// Set white only if grayscale exceeds dynamic threshold (preserve arcs)
-			if gray > threshold {
-				binaryImg.Set(x, y, color.White)
-			}
-		}
-	}
+	binaryImg.Threshold = nanoOled.getDynamicThreshold()
+	draw.Draw(binaryImg, grayImg.Bounds(), grayImg, grayImg.Bounds().Min, draw.Src)
 
-	nanoOled.image = binaryImg
+	nanoOled.reset(binaryImg)
 	return nil
 }
 
-// Send - Flush image buffer to OLED screen
+// Send - Flush image buffer to OLED screen. Failed I2C writes are retried (see Options.Retries)
+// and after ReinitAfter failures in a row the next Send re-initializes the panel first.
 func (nanoOled *NanoOled) Send() error {
-	if nanoOled.rotationState == false {
-		switch nanoOled.rotation {
-		case 90:
-			nanoOled.image = imaging.Rotate90(nanoOled.image)
-			nanoOled.rotationState = true
-		case 180:
-			nanoOled.image = imaging.Rotate180(nanoOled.image)
-			nanoOled.rotationState = true
-		case 270:
-			nanoOled.image = imaging.Rotate270(nanoOled.image)
-			nanoOled.rotationState = true
-		default:
-		}
-	}
+	img := nanoOled.image
+	pix := nanoOled.buf[1:]
 
-	imgW := nanoOled.image.Bounds().Dx()
-	imgH := nanoOled.image.Bounds().Dy()
-
-	endX := 0 + imgW
-	endY := 0 + imgH
-
-	if endX >= nanoOled.w {
-		endX = nanoOled.w
-	}
-	if endY >= nanoOled.h {
-		endY = nanoOled.h
+	// Upright and upside-down buffers already have the panel layout
+	if nanoOled.rotation == 0 || nanoOled.rotation == 180 {
+		copy(pix, img.Pix)
+		return nanoOled.draw()
 	}
 
-	// Get dynamic threshold (adapt to font rendering)
-	threshold := nanoOled.getDynamicThreshold()
-	
-	var imgI, imgY int
-	for i := 0; i < endX; i++ {
-		imgY = 0
-		for j := 0; j < endY; j++ {
-			r, g, b, _ := nanoOled.image.At(imgI, imgY).RGBA()
-			var v byte
-			// Precise grayscale judgment (preserve high-brightness pixels only)
-			gray := (r + g + b) / 3
-			if gray > uint32(threshold) {
-				v = 0x1
-			} else {
-				v = 0x0
-			}
-			if err := nanoOled.setPixel(i, j, v); err != nil {
-				return err
+	// Quarter turns: map every panel pixel back to the image (90 is counter-clockwise,
+	// 270 is the same mapping on a controller flipped by 180)
+	for i := range pix {
+		pix[i] = 0
+	}
+	imgW := img.Rect.Dx()
+	for y := 0; y < nanoOled.h; y++ {
+		for x := 0; x < nanoOled.w; x++ {
+			if img.Bit(imgW-1-y, x) {
+				pix[x+(y/8)*nanoOled.w] |= 1 << uint(y&7)
 			}
-			imgY++
 		}
-		imgI++
 	}
 	return nanoOled.draw()
 }
 
 // Clear - Clear OLED buffer and screen
 func (nanoOled *NanoOled) Clear() error {
-	if nanoOled.rotation == 90 || nanoOled.rotation == 270 {
-		nanoOled.image = image.NewNRGBA(image.Rect(0, 0, 64, 128))
-	} else {
-		nanoOled.image = image.NewNRGBA(image.Rect(0, 0, 128, 64))
-	}
+	nanoOled.reset(nanoOled.newImage())
 	for i := 1; i < len(nanoOled.buf); i++ {
 		nanoOled.buf[i] = 0
 	}
 	return nanoOled.draw()
 }
 
-// setPixel - Set single pixel value in buffer
-func (nanoOled *NanoOled) setPixel(x, y int, v byte) error {
-	if x >= nanoOled.w || y >= nanoOled.h {
-		return fmt.Errorf("coordinate(%d,%d) out of screen(%dx%d)", x, y, nanoOled.w, nanoOled.h)
-	}
-	if v > 1 {
-		return fmt.Errorf("pixel value must be 0 or 1, current:%d", v)
-	}
-	i := 1 + x + (y/8)*nanoOled.w
-	if v == 0 {
-		nanoOled.buf[i] &= ^(1 << uint((y & 7)))
-	} else {
-		nanoOled.buf[i] |= 1 << uint((y & 7))
+// dirtyWindow - Smallest page/column window covering every byte that differs from sent
+func (nanoOled *NanoOled) dirtyWindow() (page0, page1, col0, col1 int, dirty bool) {
+	page0, col0 = nanoOled.h/8, nanoOled.w
+	page1, col1 = -1, -1
+	for p := 0; p < nanoOled.h/8; p++ {
+		row := 1 + p*nanoOled.w
+		for x := 0; x < nanoOled.w; x++ {
+			if nanoOled.buf[row+x] == nanoOled.sent[row+x] {
+				continue
+			}
+			if p < page0 {
+				page0 = p
+			}
+			page1 = p
+			if x < col0 {
+				col0 = x
+			}
+			if x > col1 {
+				col1 = x
+			}
+		}
 	}
-	return nil
+	return page0, page1, col0, col1, page1 >= 0
 }
 
-// draw - Send pixel buffer to OLED via I2C
+// draw - Send changed part of pixel buffer to OLED via I2C
 func (nanoOled *NanoOled) draw() error {
-	if err := nanoOled.dev.Write([]byte{
-		0xa4,     // Normal display mode
-		0x40 | 0, // Set start line
-		0x21, 0, uint8(nanoOled.w), // Set column range
-		0x22, 0, 7,                 // Set page range
-	}); err != nil {
-		return fmt.Errorf("draw init failed: %w", err)
-	}
-	return nanoOled.dev.Write(nanoOled.buf)
+	if err := nanoOled.reinitIfFailing(); err != nil {
+		return err
+	}
+	// RAM writes during a scroll corrupt the picture, stop it first
+	if nanoOled.scrolling {
+		if err := nanoOled.StopScroll(); err != nil {
+			return err
+		}
+	}
+	if nanoOled.hwFlipped != nanoOled.flipped() {
+		if err := nanoOled.applyOrientation(); err != nil {
+			return err
+		}
+	}
+
+	page0, page1, col0, col1 := 0, nanoOled.h/8-1, 0, nanoOled.w-1
+	if nanoOled.sent != nil {
+		var dirty bool
+		if page0, page1, col0, col1, dirty = nanoOled.dirtyWindow(); !dirty {
+			return nil
+		}
+	}
+
+	if err := nanoOled.ctrl.drawWindow(nanoOled.dev, nanoOled.buf, nanoOled.w, page0, page1, col0, col1); err != nil {
+		// Panel content is unknown now, resend everything next time
+		nanoOled.sent = nil
+		return err
+	}
+	if nanoOled.sent == nil {
+		nanoOled.sent = make([]byte, len(nanoOled.buf))
+	}
+	copy(nanoOled.sent, nanoOled.buf)
+	return nil
 }
 
 // SetFontSize - Set current font size (max 32 to avoid screen overflow)
-func (nanoOled *NanoOled) SetFontSize(size float64) {
+func (canvas *Canvas) SetFontSize(size float64) {
 	if size > 32 {
 		size = 32
 	}
-	nanoOled.fontSize = size
+	canvas.fontSize = size
 }
 
 // SetBold - Toggle bold font mode
-func (nanoOled *NanoOled) SetBold(isBold bool) {
+func (canvas *Canvas) SetBold(isBold bool) {
+	canvas.bold = isBold
 	if isBold {
-		nanoOled.currentFont = nanoOled.boldFont
+		canvas.currentFont = canvas.res.boldFont
 	} else {
-		nanoOled.currentFont = nanoOled.normalFont
+		canvas.currentFont = canvas.res.normalFont
 	}
 }
 
 // Text - Draw text to image buffer (anti-aliasing + equal vertical width + arc preservation)
 // Fix: Remove incorrect Metrics call, use compatible baseline calibration
-func (nanoOled *NanoOled) Text(x int, y int, text string, textColor bool) {
+func (canvas *Canvas) Text(x int, y int, text string, textColor bool) {
 	// Boundary check: prevent text from exceeding screen
 	if x < 0 {
 		x = 0
@@ -373,138 +485,101 @@ func (nanoOled *NanoOled) Text(x int, y int, text string, textColor bool) {
 		y = 0
 	}
 	// Calculate max Y to avoid overflow
-	maxY := nanoOled.h - int(nanoOled.fontSize) - 2
+	_, lh := canvas.Size()
+	maxY := lh - int(canvas.fontSize) - 2
+	if canvas.font != nil {
+		maxY = lh - canvas.face().Metrics().Height.Ceil()
+	}
 	if y > maxY {
 		y = maxY
 	}
 
-	// Set text color
-	c := color.White
-	if !textColor {
-		c = color.Black
-	}
-
-	// Initialize freetype context with optimized anti-alias settings
-	cr := freetype.NewContext()
-	cr.SetDPI(FixedDPI)
-	cr.SetFont(nanoOled.currentFont)
-	cr.SetFontSize(nanoOled.fontSize)
-	cr.SetHinting(font.HintingFull) // Full hinting for clear font edges
-	cr.SetSrc(image.NewUniform(c))
-	cr.SetDst(nanoOled.image)
-	cr.SetClip(nanoOled.image.Bounds())
-
-	// Calculate baseline by font size (offset adapts to vertical spacing)
-	var baselineOffset float64
-	switch {
-	case nanoOled.fontSize <= 12:
-		baselineOffset = 2.0 // Small font offset
-	case nanoOled.fontSize <= 24:
-		baselineOffset = 3.0 // Medium font offset
-	default:
-		baselineOffset = 4.0 // Large font offset
-	}
-	// Calculate baseline for vertical centering (equal top/bottom spacing)
-	baseY := y + int(cr.PointToFixed(nanoOled.fontSize)>>6) - int(baselineOffset)
-	pt := freetype.Pt(x, baseY)
+	// Binarize anti-aliased edges with the threshold for this font size
+	canvas.image.Threshold = canvas.getDynamicThreshold()
 
 	// Draw text with anti-aliasing
-	if _, err := cr.DrawString(text, pt); err != nil {
-		fmt.Printf("draw text failed: %v\n", err)
-	}
+	canvas.drawString(x, canvas.baseline(y), text, textColor)
 }
 
 // Pixel - Draw single pixel to image buffer
-func (nanoOled *NanoOled) Pixel(x int, y int, pixColor bool) {
+func (canvas *Canvas) Pixel(x int, y int, pixColor bool) {
 	// Boundary check
-	if x < 0 || x >= nanoOled.w || y < 0 || y >= nanoOled.h {
+	lw, lh := canvas.Size()
+	if x < 0 || x >= lw || y < 0 || y >= lh {
 		return
 	}
-	rColor := color.White
-	if !pixColor {
-		rColor = color.Black
-	}
-	nanoOled.image.Set(x, y, rColor)
+	canvas.set(x, y, pixColor)
 }
 
 // LineH - Draw horizontal line (optimized for equal vertical width)
-func (nanoOled *NanoOled) LineH(x int, y int, length int, lineColor bool) {
+func (canvas *Canvas) LineH(x int, y int, length int, lineColor bool) {
 	// Boundary check
+	lw, lh := canvas.Size()
 	if x < 0 {
 		x = 0
 	}
-	if y < 0 || y >= nanoOled.h {
+	if y < 0 || y >= lh {
 		return
 	}
 	endX := x + length
-	if endX >= nanoOled.w {
-		endX = nanoOled.w - 1
+	if endX >= lw {
+		endX = lw - 1
 	}
 
-	lColor := color.White
-	if !lineColor {
-		lColor = color.Black
-	}
 	px := x
 	for px <= endX {
-		nanoOled.image.Set(px, y, lColor)
+		canvas.set(px, y, lineColor)
 		px++
 	}
 }
 
 // LineV - Draw vertical line (optimized for equal vertical width)
-func (nanoOled *NanoOled) LineV(x int, y int, length int, lineColor bool) {
+func (canvas *Canvas) LineV(x int, y int, length int, lineColor bool) {
 	// Boundary check
-	if x < 0 || x >= nanoOled.w {
+	lw, lh := canvas.Size()
+	if x < 0 || x >= lw {
 		return
 	}
 	if y < 0 {
 		y = 0
 	}
 	endY := y + length
-	if endY >= nanoOled.h {
-		endY = nanoOled.h - 1
+	if endY >= lh {
+		endY = lh - 1
 	}
 
-	lColor := color.White
-	if !lineColor {
-		lColor = color.Black
-	}
 	py := y
 	for py <= endY {
-		nanoOled.image.Set(x, py, lColor)
+		canvas.set(x, py, lineColor)
 		py++
 	}
 }
 
 // Rect - Draw filled rectangle (optimized for equal vertical width)
-func (nanoOled *NanoOled) Rect(MinX int, MinY int, MaxX int, MaxY int, rectColor bool) {
+func (canvas *Canvas) Rect(MinX int, MinY int, MaxX int, MaxY int, rectColor bool) {
 	// Boundary check: ensure rectangle is within screen
+	lw, lh := canvas.Size()
 	if MinX < 0 {
 		MinX = 0
 	}
 	if MinY < 0 {
 		MinY = 0
 	}
-	if MaxX >= nanoOled.w {
-		MaxX = nanoOled.w - 1
+	if MaxX >= lw {
+		MaxX = lw - 1
 	}
-	if MaxY >= nanoOled.h {
-		MaxY = nanoOled.h - 1
+	if MaxY >= lh {
+		MaxY = lh - 1
 	}
 	if MinX > MaxX || MinY > MaxY {
 		return
 	}
 
-	rColor := color.White
-	if !rectColor {
-		rColor = color.Black
-	}
 	py := MinY
 	for py <= MaxY {
 		px := MinX
 		for px <= MaxX {
-			nanoOled.image.Set(px, py, rColor)
+			canvas.set(px, py, rectColor)
 			px++
 		}
 		py++
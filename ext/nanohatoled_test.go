@@ -0,0 +1,172 @@
+package nanohatoled
+
+import (
+	"bytes"
+	"image"
+	"testing"
+)
+
+// testFontDir - DejaVu fonts shipped with the daemon
+const testFontDir = "../files/NanoHatOLED"
+
+// recordBus - Bus that keeps a copy of every write
+type recordBus struct {
+	writes [][]byte
+}
+
+func (b *recordBus) Write(buf []byte) error {
+	b.writes = append(b.writes, append([]byte(nil), buf...))
+	return nil
+}
+
+func (b *recordBus) Close() error { return nil }
+
+// testOled - Initialized 128x64 panel on a recording bus, writes of init already dropped
+func testOled(t testing.TB, c Controller) (*NanoOled, *recordBus) {
+	t.Helper()
+	opts := DefaultOptions()
+	opts.FontDir = testFontDir
+	opts.Controller = c
+	rec := &recordBus{}
+	oled, err := newNanoOled(rec, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := oled.init(); err != nil {
+		t.Fatal(err)
+	}
+	oled.New(0)
+	if err := oled.Send(); err != nil {
+		t.Fatal(err)
+	}
+	rec.writes = nil
+	return oled, rec
+}
+
+func checkWrites(t *testing.T, got [][]byte, want ...[]byte) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Fatalf("got %d writes % x, want %d", len(got), got, len(want))
+	}
+	for i := range want {
+		if !bytes.Equal(got[i], want[i]) {
+			t.Errorf("write %d: got % x, want % x", i, got[i], want[i])
+		}
+	}
+}
+
+func TestDrawWindow(t *testing.T) {
+	oled, rec := testOled(t, SSD1306)
+	oled.Pixel(10, 20, true)
+	if err := oled.Send(); err != nil {
+		t.Fatal(err)
+	}
+	checkWrites(t, rec.writes,
+		[]byte{0x00, 0xa4, 0x40, 0x21, 0x0a, 0x0a, 0x22, 0x02, 0x02},
+		[]byte{0x40, 0x10})
+
+	// Two corners span the window between them
+	rec.writes = nil
+	oled.Pixel(3, 9, true)
+	oled.Pixel(12, 30, true)
+	if err := oled.Send(); err != nil {
+		t.Fatal(err)
+	}
+	data := make([]byte, 1+10*3)
+	data[0] = 0x40
+	data[1+0*10+0] = 0x02 // (3,9): page 1, bit 1
+	data[1+2*10+9] = 0x40 // (12,30): page 3, bit 6
+	data[1+1*10+7] = 0x10 // (10,20) from before, inside the window
+	checkWrites(t, rec.writes,
+		[]byte{0x00, 0xa4, 0x40, 0x21, 0x03, 0x0c, 0x22, 0x01, 0x03},
+		data)
+}
+
+func TestDrawUnchanged(t *testing.T) {
+	oled, rec := testOled(t, SSD1306)
+	if err := oled.Send(); err != nil {
+		t.Fatal(err)
+	}
+	oled.Pixel(5, 5, true)
+	oled.Pixel(5, 5, false)
+	if err := oled.Send(); err != nil {
+		t.Fatal(err)
+	}
+	checkWrites(t, rec.writes)
+}
+
+func TestDrawFullFrame(t *testing.T) {
+	oled, rec := testOled(t, SSD1306)
+	oled.sent = nil
+	oled.Pixel(0, 0, true)
+	if err := oled.Send(); err != nil {
+		t.Fatal(err)
+	}
+	checkWrites(t, rec.writes,
+		[]byte{0x00, 0xa4, 0x40, 0x21, 0x00, 0x7f, 0x22, 0x00, 0x07},
+		oled.buf)
+}
+
+func TestDrawSH1106Pages(t *testing.T) {
+	oled, rec := testOled(t, SH1106)
+	oled.Pixel(10, 20, true)
+	oled.Pixel(11, 33, true)
+	if err := oled.Send(); err != nil {
+		t.Fatal(err)
+	}
+	// Columns start at RAM column 2, one page address per transfer
+	checkWrites(t, rec.writes,
+		[]byte{0x00, 0xb2, 0x0c, 0x10},
+		[]byte{0x40, 0x10, 0x00},
+		[]byte{0x00, 0xb3, 0x0c, 0x10},
+		[]byte{0x40, 0x00, 0x00},
+		[]byte{0x00, 0xb4, 0x0c, 0x10},
+		[]byte{0x40, 0x00, 0x02})
+}
+
+func TestInitRestoresTuning(t *testing.T) {
+	oled, rec := testOled(t, SSD1306)
+	if err := oled.SetPrecharge(2, 15); err != nil {
+		t.Fatal(err)
+	}
+	if err := oled.SetVCOMH(VCOMH065); err != nil {
+		t.Fatal(err)
+	}
+	rec.writes = nil
+	if err := oled.init(); err != nil {
+		t.Fatal(err)
+	}
+	checkWrites(t, rec.writes[1:],
+		[]byte{0x00, 0xd9, 0xf2},
+		[]byte{0x00, 0xdb, 0x00})
+}
+
+func TestFrameRotation(t *testing.T) {
+	// Where the logical top left pixel lights up on the panel
+	for rotation, want := range map[int]image.Point{0: {0, 0}, 90: {0, 63}, 180: {127, 63}, 270: {127, 0}} {
+		oled, _ := testOled(t, SSD1306)
+		oled.New(rotation)
+		oled.Pixel(0, 0, true)
+		if err := oled.Send(); err != nil {
+			t.Fatal(err)
+		}
+		frame := oled.Frame()
+		for y := 0; y < frame.Rect.Dy(); y++ {
+			for x := 0; x < frame.Rect.Dx(); x++ {
+				if lit := frame.GrayAt(x, y).Y != 0; lit != (image.Point{x, y} == want) {
+					t.Errorf("rotation %d: pixel (%d,%d) lit %v", rotation, x, y, lit)
+				}
+			}
+		}
+	}
+}
+
+func TestSetVCOMHPerController(t *testing.T) {
+	for c, want := range map[Controller]byte{SSD1306: 0x30, SSD1309: 0x34, SH1106: 0x35} {
+		oled, rec := testOled(t, c)
+		if err := oled.SetVCOMH(0x35); err != nil {
+			t.Fatal(err)
+		}
+		checkWrites(t, rec.writes, []byte{0x00, 0xdb, want})
+	}
+}
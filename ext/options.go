@@ -0,0 +1,103 @@
+package nanohatoled
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// Options - Hardware settings used by OpenWithOptions
+type Options struct {
+	Bus        string     // I2C device path
+	Address    uint16     // I2C slave address (0x3C or 0x3D)
+	Controller Controller // Display driver IC
+	Width      int        // Panel width in pixels
+	Height     int        // Panel height in pixels (32 or 64)
+	FontDir    string     // Directory holding the DejaVu fonts
+
+	// Fonts tried in file name order for runes the current font lacks (CJK, symbols).
+	// Empty uses the "fallback" directory inside FontDir.
+	FallbackFontDir string
+
+	// User PNG icons added on open (see LoadIcons), empty uses "icons" inside FontDir
+	IconDir string
+
+	ButtonPins      []string // GPIO names for K1, K2, K3
+	ButtonActiveLow bool     // Buttons pull the line low when pressed
+	NoButtons       bool     // Skip GPIO setup entirely
+
+	// GPIO character device for the buttons ("gpiochip0" or a path) and line offsets
+	// on it for K1, K2, K3. Empty ButtonLines uses ButtonPins as offsets. Without the
+	// device the buttons fall back to ButtonPins through sysfs.
+	ButtonChip  string
+	ButtonLines []int
+	ButtonBias  Bias // Pull resistor on the button lines (character device only)
+
+	Retries        int           // Extra attempts for a failed I2C write
+	RetryDelay     time.Duration // Pause before the first retry, doubled for each further one
+	ReinitAfter    int           // Failed writes in a row after which Send re-runs the init sequence, 0 never
+	ReinitMaxDelay time.Duration // Longest pause between re-init attempts while they keep failing
+	Logger         Logger        // Receives I2C error and recovery messages, nil discards them
+
+	// Open even when no panel answers: drawing goes to the frame only until Probe finds
+	// the panel. Buttons work either way.
+	Hotplug bool
+}
+
+// DefaultOptions - Settings matching the stock NanoHat OLED
+func DefaultOptions() Options {
+	return Options{
+		Bus:        "/dev/i2c-0",
+		Address:    0x3C,
+		Controller: SSD1306,
+		Width:      128,
+		Height:     64,
+		FontDir:    defaultFontDir,
+		ButtonPins: []string{"0", "2", "3"},
+		ButtonChip: "gpiochip0",
+
+		Retries:        3,
+		RetryDelay:     2 * time.Millisecond,
+		ReinitAfter:    3,
+		ReinitMaxDelay: 5 * time.Second,
+	}
+}
+
+// validate - Check options against what the controller supports
+func (opts *Options) validate() error {
+	if opts.Address != 0x3C && opts.Address != 0x3D {
+		return fmt.Errorf("unsupported I2C address 0x%02X (want 0x3C or 0x3D)", opts.Address)
+	}
+	if opts.Width <= 0 || opts.Width > 128 {
+		return fmt.Errorf("unsupported width %d (1-128)", opts.Width)
+	}
+	if opts.Height != 32 && opts.Height != 64 {
+		return fmt.Errorf("unsupported height %d (32 or 64)", opts.Height)
+	}
+	if !opts.NoButtons && len(opts.ButtonPins) > 3 {
+		return &ButtonError{fmt.Errorf("too many button pins: %d (max 3)", len(opts.ButtonPins))}
+	}
+	if !opts.NoButtons && len(opts.ButtonLines) > 3 {
+		return &ButtonError{fmt.Errorf("too many button lines: %d (max 3)", len(opts.ButtonLines))}
+	}
+	if opts.Retries < 0 || opts.RetryDelay < 0 || opts.ReinitAfter < 0 || opts.ReinitMaxDelay < 0 {
+		return fmt.Errorf("negative I2C retry settings")
+	}
+	return nil
+}
+
+// buttonLines - Line offsets of the buttons on ButtonChip
+func (opts *Options) buttonLines() ([]int, error) {
+	if len(opts.ButtonLines) > 0 {
+		return opts.ButtonLines, nil
+	}
+	lines := make([]int, len(opts.ButtonPins))
+	for i, name := range opts.ButtonPins {
+		n, err := strconv.Atoi(name)
+		if err != nil {
+			return nil, fmt.Errorf("button pin %q is not a line offset, set ButtonLines", name)
+		}
+		lines[i] = n
+	}
+	return lines, nil
+}
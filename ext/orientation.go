@@ -0,0 +1,55 @@
+package nanohatoled
+
+import "fmt"
+
+const (
+	ssd1306SegRemapNormal  = 0xa1 // Column 0 on the right edge of the NanoHat mounting
+	ssd1306SegRemapFlipped = 0xa0
+	ssd1306ComScanNormal   = 0xc8 // Scan COM[N-1] to COM0
+	ssd1306ComScanFlipped  = 0xc0
+	ssd1306NormalDisplay   = 0xa6
+	ssd1306InvertDisplay   = 0xa7
+)
+
+// flipped - Rotations that turn the panel upside down, done by the controller
+func (nanoOled *NanoOled) flipped() bool {
+	return nanoOled.rotation == 180 || nanoOled.rotation == 270
+}
+
+// applyOrientation - Program segment remap and COM scan direction for the current rotation
+func (nanoOled *NanoOled) applyOrientation() error {
+	seg, com := byte(ssd1306SegRemapNormal), byte(ssd1306ComScanNormal)
+	if nanoOled.flipped() {
+		seg, com = ssd1306SegRemapFlipped, ssd1306ComScanFlipped
+	}
+	if err := writeCommands(nanoOled.dev, seg, com); err != nil {
+		return fmt.Errorf("set orientation failed: %w", err)
+	}
+	nanoOled.hwFlipped = nanoOled.flipped()
+	// Segment remap only applies to new RAM writes, redraw everything
+	nanoOled.sent = nil
+	return nil
+}
+
+// SetInvert - Invert the whole panel in hardware (lit pixels turn dark and vice versa)
+func (nanoOled *NanoOled) SetInvert(invert bool) error {
+	cmd := byte(ssd1306NormalDisplay)
+	if invert {
+		cmd = ssd1306InvertDisplay
+	}
+	if err := writeCommands(nanoOled.dev, cmd); err != nil {
+		return fmt.Errorf("set invert failed: %w", err)
+	}
+	nanoOled.inverted = invert
+	return nil
+}
+
+// Inverted - Report whether hardware inversion is active
+func (nanoOled *NanoOled) Inverted() bool {
+	return nanoOled.inverted
+}
+
+// Rotation - Current rotation in degrees
+func (nanoOled *NanoOled) Rotation() int {
+	return nanoOled.rotation
+}
@@ -0,0 +1,140 @@
+package nanohatoled
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// reinitFirstDelay - Pause after a failed re-init before the next attempt, doubled up to
+// Options.ReinitMaxDelay while attempts keep failing
+const reinitFirstDelay = time.Second
+
+// errReinitWait - Send while the panel keeps failing and the next re-init is not due yet
+var errReinitWait = errors.New("panel not responding, waiting to re-initialize")
+
+// Logger - Destination for driver messages, *log.Logger satisfies it
+type Logger interface {
+	Printf(format string, v ...interface{})
+}
+
+// logf - Log through log, if any
+func logf(log Logger, format string, v ...interface{}) {
+	if log != nil {
+		log.Printf(format, v...)
+	}
+}
+
+// IOStats - I2C write counters of a panel
+type IOStats struct {
+	Writes      uint64 // Writes requested
+	Retries     uint64 // Repeated attempts after a failed one
+	Failures    uint64 // Writes that still failed after all retries
+	Reinits     uint64 // Init sequence re-runs after failures in a row
+	Consecutive int    // Failed writes since the last good one
+}
+
+// retryBus - Bus that repeats failed writes with exponential backoff and counts them
+type retryBus struct {
+	bus
+	retries     int           // Extra attempts per write
+	delay       time.Duration // Pause before the first extra attempt, doubled for each further one
+	reinitAfter int           // Failed writes in a row that make draw re-run init, 0 never does
+	reinitMax   time.Duration // Longest pause between failing re-init attempts
+	reinitDelay time.Duration // Pause after the last failed re-init
+	reinitAt    time.Time     // Earliest time for the next re-init attempt, zero before the first
+	now         func() time.Time
+	log         Logger // May be nil
+	stats       IOStats
+}
+
+// newRetryBus - Wrap dev with the retry settings of opts
+func newRetryBus(dev bus, opts Options) *retryBus {
+	return &retryBus{
+		bus:         dev,
+		retries:     opts.Retries,
+		delay:       opts.RetryDelay,
+		reinitAfter: opts.ReinitAfter,
+		reinitMax:   opts.ReinitMaxDelay,
+		now:         time.Now,
+		log:         opts.Logger,
+	}
+}
+
+// logf - Log through the configured logger, if any
+func (b *retryBus) logf(format string, v ...interface{}) {
+	logf(b.log, format, v...)
+}
+
+// Write - Write buf, retrying a failed transfer. Only the first failure of a run and the
+// recovery from it are logged.
+func (b *retryBus) Write(buf []byte) error {
+	b.stats.Writes++
+	err := b.bus.Write(buf)
+	delay := b.delay
+	for i := 1; err != nil && i <= b.retries; i++ {
+		time.Sleep(delay)
+		delay *= 2
+		b.stats.Retries++
+		if err = b.bus.Write(buf); err == nil {
+			b.logf("I2C write recovered after %d retries", i)
+		}
+	}
+	if err != nil {
+		b.stats.Failures++
+		b.stats.Consecutive++
+		if b.stats.Consecutive == 1 {
+			b.logf("I2C write failed after %d retries: %v", b.retries, err)
+		}
+		return err
+	}
+	if b.stats.Consecutive > 0 {
+		b.logf("I2C writes recovered after %d failures", b.stats.Consecutive)
+	}
+	b.stats.Consecutive = 0
+	b.reinitDelay, b.reinitAt = 0, time.Time{}
+	return nil
+}
+
+// IOStats - I2C write counters, all zero on a virtual display
+func (nanoOled *NanoOled) IOStats() IOStats {
+	if b, ok := nanoOled.dev.(*retryBus); ok {
+		return b.stats
+	}
+	return IOStats{}
+}
+
+// reinitIfFailing - Re-run init when enough writes failed in a row: the panel may have
+// reset or lost its settings. init also makes the next draw send the whole frame.
+// Failed attempts back off from reinitFirstDelay up to reinitMax, and draws in between
+// fail with errReinitWait without touching the bus.
+func (nanoOled *NanoOled) reinitIfFailing() error {
+	b, ok := nanoOled.dev.(*retryBus)
+	if !ok || b.reinitAfter <= 0 || b.stats.Consecutive < b.reinitAfter {
+		return nil
+	}
+	if b.now().Before(b.reinitAt) {
+		return errReinitWait
+	}
+	first := b.reinitAt.IsZero()
+	if first {
+		b.logf("%d I2C writes failed in a row, re-initializing panel", b.stats.Consecutive)
+	}
+	b.stats.Reinits++
+	if err := nanoOled.init(); err != nil {
+		if first {
+			b.logf("Panel re-init failed, retrying up to every %v: %v", b.reinitMax, err)
+		}
+		b.reinitDelay *= 2
+		if b.reinitDelay < reinitFirstDelay {
+			b.reinitDelay = reinitFirstDelay
+		}
+		if b.reinitDelay > b.reinitMax {
+			b.reinitDelay = b.reinitMax
+		}
+		b.reinitAt = b.now().Add(b.reinitDelay)
+		return fmt.Errorf("panel re-init failed: %w", err)
+	}
+	b.logf("Panel re-initialized")
+	return nil
+}
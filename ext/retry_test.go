@@ -0,0 +1,123 @@
+package nanohatoled
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+// failBus - recordBus whose writes fail while fail is set
+type failBus struct {
+	recordBus
+	fail     bool
+	attempts int
+}
+
+func (b *failBus) Write(buf []byte) error {
+	b.attempts++
+	if b.fail {
+		return errors.New("i2c: no ACK")
+	}
+	return b.recordBus.Write(buf)
+}
+
+// testRetryOled - testOled on a failing bus behind a retryBus with a fake clock
+func testRetryOled(t *testing.T) (*NanoOled, *failBus, *retryBus, *logRecorder, *time.Time) {
+	oled, _ := testOled(t, SSD1306)
+	fb := &failBus{}
+	log := &logRecorder{}
+	opts := DefaultOptions()
+	opts.RetryDelay = 0
+	opts.Logger = log
+	rb := newRetryBus(fb, opts)
+	now := time.Unix(0, 0)
+	rb.now = func() time.Time { return now }
+	oled.dev = rb
+	return oled, fb, rb, log, &now
+}
+
+func TestRetryBusCounts(t *testing.T) {
+	oled, fb, rb, log, _ := testRetryOled(t)
+	fb.fail = true
+	for i := 0; i < 2; i++ {
+		oled.Pixel(i, 0, true)
+		if err := oled.Send(); err == nil {
+			t.Fatal("Send on a failing bus succeeded")
+		}
+	}
+	// One try and three retries per write, one failed write per Send
+	if fb.attempts != 8 {
+		t.Errorf("%d attempts, want 8", fb.attempts)
+	}
+	want := IOStats{Writes: 2, Retries: 6, Failures: 2, Consecutive: 2}
+	if rb.stats != want {
+		t.Errorf("stats %+v, want %+v", rb.stats, want)
+	}
+
+	fb.fail = false
+	if err := oled.Send(); err != nil {
+		t.Fatal(err)
+	}
+	if rb.stats.Consecutive != 0 || rb.stats.Reinits != 0 {
+		t.Errorf("stats after recovery %+v", rb.stats)
+	}
+	msgs := log.messages()
+	if len(msgs) != 2 || !strings.Contains(msgs[0], "failed") || !strings.Contains(msgs[1], "recovered") {
+		t.Errorf("log %q", msgs)
+	}
+}
+
+func TestReinitBackoff(t *testing.T) {
+	oled, fb, rb, log, now := testRetryOled(t)
+	fb.fail = true
+	for i := 0; i < rb.reinitAfter; i++ {
+		oled.Pixel(i, 0, true)
+		oled.Send()
+	}
+	if rb.stats.Consecutive != rb.reinitAfter || rb.stats.Reinits != 0 {
+		t.Fatalf("stats %+v", rb.stats)
+	}
+
+	// Attempts are due after 0, 1, 2, 4, 5, 5 seconds
+	for _, wait := range []time.Duration{0, time.Second, 2 * time.Second, 4 * time.Second, 5 * time.Second, 5 * time.Second} {
+		*now = now.Add(wait - time.Millisecond)
+		reinits, attempts := rb.stats.Reinits, fb.attempts
+		if wait > 0 {
+			if err := oled.Send(); err != errReinitWait {
+				t.Fatalf("Send before the re-init was due: %v", err)
+			}
+			if rb.stats.Reinits != reinits || fb.attempts != attempts {
+				t.Fatalf("bus used before the re-init was due")
+			}
+		}
+		*now = now.Add(time.Millisecond)
+		if err := oled.Send(); err == nil || err == errReinitWait {
+			t.Fatalf("Send after %v: %v", wait, err)
+		}
+		if rb.stats.Reinits != reinits+1 {
+			t.Fatalf("after %v: %d re-inits, want %d", wait, rb.stats.Reinits, reinits+1)
+		}
+	}
+	// First failure, start of re-init and its failure only
+	if msgs := log.messages(); len(msgs) != 3 {
+		t.Errorf("log %q", msgs)
+	}
+
+	// The panel answers again: the next due attempt re-inits and sends the whole frame
+	fb.fail = false
+	*now = now.Add(5 * time.Second)
+	if err := oled.Send(); err != nil {
+		t.Fatal(err)
+	}
+	if rb.stats.Consecutive != 0 || !rb.reinitAt.IsZero() {
+		t.Errorf("stats %+v, next re-init %v", rb.stats, rb.reinitAt)
+	}
+	if last := fb.writes[len(fb.writes)-1]; len(last) != len(oled.buf) {
+		t.Errorf("last write of %d bytes, want the whole frame", len(last))
+	}
+	msgs := log.messages()
+	if len(msgs) != 5 || !strings.Contains(msgs[3], "recovered") || !strings.Contains(msgs[4], "re-initialized") {
+		t.Errorf("log %q", msgs)
+	}
+}
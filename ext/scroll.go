@@ -0,0 +1,110 @@
+package nanohatoled
+
+import "fmt"
+
+// ScrollDirection - Horizontal direction of a hardware scroll
+type ScrollDirection int
+
+const (
+	ScrollRight ScrollDirection = iota
+	ScrollLeft
+)
+
+// ScrollSpeed - Frames between scroll steps, encoded as the SSD1306 interval code
+type ScrollSpeed byte
+
+const (
+	Scroll2Frames   ScrollSpeed = 0x07
+	Scroll3Frames   ScrollSpeed = 0x04
+	Scroll4Frames   ScrollSpeed = 0x05
+	Scroll5Frames   ScrollSpeed = 0x00
+	Scroll25Frames  ScrollSpeed = 0x06
+	Scroll64Frames  ScrollSpeed = 0x01
+	Scroll128Frames ScrollSpeed = 0x02
+	Scroll256Frames ScrollSpeed = 0x03
+)
+
+// checkScroll - Validate controller support and page range for a scroll command
+func (nanoOled *NanoOled) checkScroll(startPage, endPage int) error {
+	if !nanoOled.ctrl.canScroll() {
+		return fmt.Errorf("hardware scrolling not supported by controller")
+	}
+	pages := nanoOled.h / 8
+	if startPage < 0 || endPage >= pages || startPage > endPage {
+		return fmt.Errorf("scroll pages %d-%d out of range 0-%d", startPage, endPage, pages-1)
+	}
+	return nil
+}
+
+// StartHorizontalScroll - Continuously scroll pages startPage..endPage left or right
+func (nanoOled *NanoOled) StartHorizontalScroll(dir ScrollDirection, startPage, endPage int, speed ScrollSpeed) error {
+	if err := nanoOled.checkScroll(startPage, endPage); err != nil {
+		return err
+	}
+	cmd := byte(ssd1306RightHorizontalScroll)
+	if nanoOled.scrollsLeft(dir) {
+		cmd = ssd1306LeftHorizontalScroll
+	}
+	return nanoOled.startScroll(nanoOled.ctrl.scrollSetup(cmd, startPage, endPage, speed, 0))
+}
+
+// StartDiagonalScroll - Scroll pages startPage..endPage horizontally while moving the
+// vertical scroll area up by verticalOffset rows per step (see SetVerticalScrollArea)
+func (nanoOled *NanoOled) StartDiagonalScroll(dir ScrollDirection, startPage, endPage int, speed ScrollSpeed, verticalOffset int) error {
+	if err := nanoOled.checkScroll(startPage, endPage); err != nil {
+		return err
+	}
+	if verticalOffset < 0 || verticalOffset >= nanoOled.h {
+		return fmt.Errorf("vertical scroll offset %d out of range 0-%d", verticalOffset, nanoOled.h-1)
+	}
+	cmd := byte(ssd1306VerticalAndRightHorizontalScroll)
+	if nanoOled.scrollsLeft(dir) {
+		cmd = ssd1306VerticalAndLeftHorizontalScroll
+	}
+	return nanoOled.startScroll(nanoOled.ctrl.scrollSetup(cmd, startPage, endPage, speed, verticalOffset))
+}
+
+// SetVerticalScrollArea - Keep topRows fixed and let the following scrollRows move
+// during a diagonal scroll
+func (nanoOled *NanoOled) SetVerticalScrollArea(topRows, scrollRows int) error {
+	if !nanoOled.ctrl.canScroll() {
+		return fmt.Errorf("hardware scrolling not supported by controller")
+	}
+	if topRows < 0 || scrollRows < 0 || topRows+scrollRows > nanoOled.h {
+		return fmt.Errorf("vertical scroll area %d+%d exceeds %d rows", topRows, scrollRows, nanoOled.h)
+	}
+	return writeCommands(nanoOled.dev, ssd1306SetVerticalScrollArea, uint8(topRows), uint8(scrollRows))
+}
+
+// scrollsLeft - Whether dir maps to the controller's left scroll (swapped when flipped)
+func (nanoOled *NanoOled) scrollsLeft(dir ScrollDirection) bool {
+	return (dir == ScrollLeft) != nanoOled.hwFlipped
+}
+
+// startScroll - Send a scroll setup between stop and activate, remember that RAM is moving
+func (nanoOled *NanoOled) startScroll(setup []byte) error {
+	// Setup requires scrolling to be stopped
+	cmds := append([]byte{ssd1306DeactivateScroll}, setup...)
+	if err := writeCommands(nanoOled.dev, append(cmds, ssd1306ActivateScroll)...); err != nil {
+		return fmt.Errorf("start scroll failed: %w", err)
+	}
+	nanoOled.scrolling = true
+	// Scrolling shifts display RAM, the next frame has to be written in full
+	nanoOled.sent = nil
+	return nil
+}
+
+// StopScroll - Stop any hardware scroll, RAM is rewritten on the next Send
+func (nanoOled *NanoOled) StopScroll() error {
+	if err := writeCommands(nanoOled.dev, ssd1306DeactivateScroll); err != nil {
+		return fmt.Errorf("stop scroll failed: %w", err)
+	}
+	nanoOled.scrolling = false
+	nanoOled.sent = nil
+	return nil
+}
+
+// Scrolling - Report whether a hardware scroll is active
+func (nanoOled *NanoOled) Scrolling() bool {
+	return nanoOled.scrolling
+}
@@ -0,0 +1,70 @@
+package nanohatoled
+
+import "testing"
+
+func TestStartHorizontalScroll(t *testing.T) {
+	for c, want := range map[Controller][]byte{
+		SSD1306: {0x00, 0x2e, 0x26, 0x00, 0x01, 0x07, 0x06, 0x00, 0xff, 0x2f},
+		SSD1309: {0x00, 0x2e, 0x26, 0x00, 0x01, 0x07, 0x06, 0x00, 0x00, 0x7f, 0x2f},
+	} {
+		oled, rec := testOled(t, c)
+		if err := oled.StartHorizontalScroll(ScrollRight, 1, 6, Scroll2Frames); err != nil {
+			t.Fatal(err)
+		}
+		checkWrites(t, rec.writes, want)
+		if !oled.Scrolling() {
+			t.Errorf("%v: not scrolling", c)
+		}
+	}
+}
+
+func TestStartDiagonalScroll(t *testing.T) {
+	for c, want := range map[Controller][]byte{
+		SSD1306: {0x00, 0x2e, 0x2a, 0x00, 0x00, 0x00, 0x07, 0x01, 0x2f},
+		SSD1309: {0x00, 0x2e, 0x2a, 0x01, 0x00, 0x00, 0x07, 0x01, 0x00, 0x7f, 0x2f},
+	} {
+		oled, rec := testOled(t, c)
+		if err := oled.StartDiagonalScroll(ScrollLeft, 0, 7, Scroll5Frames, 1); err != nil {
+			t.Fatal(err)
+		}
+		checkWrites(t, rec.writes, want)
+	}
+}
+
+func TestScrollUnsupported(t *testing.T) {
+	oled, rec := testOled(t, SH1106)
+	if err := oled.StartHorizontalScroll(ScrollLeft, 0, 7, Scroll2Frames); err == nil {
+		t.Error("horizontal scroll on SH1106 accepted")
+	}
+	if err := oled.StartDiagonalScroll(ScrollLeft, 0, 7, Scroll2Frames, 1); err == nil {
+		t.Error("diagonal scroll on SH1106 accepted")
+	}
+	if err := oled.SetVerticalScrollArea(0, 64); err == nil {
+		t.Error("vertical scroll area on SH1106 accepted")
+	}
+	if oled.Scrolling() || len(rec.writes) != 0 {
+		t.Errorf("scrolling %v, writes % x", oled.Scrolling(), rec.writes)
+	}
+}
+
+func TestSendStopsScroll(t *testing.T) {
+	oled, rec := testOled(t, SSD1306)
+	if err := oled.StartHorizontalScroll(ScrollRight, 0, 7, Scroll2Frames); err != nil {
+		t.Fatal(err)
+	}
+	rec.writes = nil
+	if err := oled.Send(); err != nil {
+		t.Fatal(err)
+	}
+	// Stop first, then the whole frame although nothing changed
+	if len(rec.writes) != 3 {
+		t.Fatalf("writes % x", rec.writes)
+	}
+	checkWrites(t, rec.writes[:1], []byte{0x00, 0x2e})
+	if last := rec.writes[2]; len(last) != len(oled.buf) {
+		t.Errorf("frame write of %d bytes, want %d", len(last), len(oled.buf))
+	}
+	if oled.Scrolling() {
+		t.Error("still scrolling")
+	}
+}
@@ -0,0 +1,375 @@
+package nanohatoled
+
+import (
+	"image"
+	"math"
+	"sort"
+)
+
+// Corner coordinates are inclusive, like Rect. Everything is clipped to the logical screen.
+
+// hspan - Set pixels x0..x1 of row y, clipped to the canvas
+func (canvas *Canvas) hspan(x0, x1, y int, on bool) {
+	b := canvas.bounds()
+	if y < b.Min.Y || y >= b.Max.Y {
+		return
+	}
+	if x0 > x1 {
+		x0, x1 = x1, x0
+	}
+	if x0 < b.Min.X {
+		x0 = b.Min.X
+	}
+	if x1 >= b.Max.X {
+		x1 = b.Max.X - 1
+	}
+	for x := x0; x <= x1; x++ {
+		canvas.set(x, y, on)
+	}
+}
+
+// linePoints - Bresenham line from (x0, y0) to (x1, y1), plot gets the step index
+func linePoints(x0, y0, x1, y1 int, plot func(i, x, y int)) {
+	dx, sx := x1-x0, 1
+	if dx < 0 {
+		dx, sx = -dx, -1
+	}
+	dy, sy := y0-y1, 1
+	if dy > 0 {
+		dy, sy = -dy, -1
+	}
+	err := dx + dy
+	for i := 0; ; i++ {
+		plot(i, x0, y0)
+		if x0 == x1 && y0 == y1 {
+			return
+		}
+		e2 := 2 * err
+		if e2 >= dy {
+			err += dy
+			x0 += sx
+		}
+		if e2 <= dx {
+			err += dx
+			y0 += sy
+		}
+	}
+}
+
+// clipLine - Cohen-Sutherland: the part of a segment inside the canvas, ends rounded to
+// pixels, ok is false when none of it is. Lines with far away ends only walk the visible steps.
+func (canvas *Canvas) clipLine(x0, y0, x1, y1 int) (cx0, cy0, cx1, cy1 int, ok bool) {
+	b := canvas.bounds()
+	minX, minY := float64(b.Min.X), float64(b.Min.Y)
+	maxX, maxY := float64(b.Max.X-1), float64(b.Max.Y-1)
+	outcode := func(x, y float64) int {
+		code := 0
+		if x < minX {
+			code |= 1
+		} else if x > maxX {
+			code |= 2
+		}
+		if y < minY {
+			code |= 4
+		} else if y > maxY {
+			code |= 8
+		}
+		return code
+	}
+
+	// Floats: products of far away coordinates overflow a 32-bit int
+	fx0, fy0, fx1, fy1 := float64(x0), float64(y0), float64(x1), float64(y1)
+	code0, code1 := outcode(fx0, fy0), outcode(fx1, fy1)
+	for code0|code1 != 0 {
+		if code0&code1 != 0 {
+			return 0, 0, 0, 0, false
+		}
+		code := code0
+		if code == 0 {
+			code = code1
+		}
+		var x, y float64
+		switch {
+		case code&4 != 0:
+			x, y = fx0+(fx1-fx0)*(minY-fy0)/(fy1-fy0), minY
+		case code&8 != 0:
+			x, y = fx0+(fx1-fx0)*(maxY-fy0)/(fy1-fy0), maxY
+		case code&1 != 0:
+			x, y = minX, fy0+(fy1-fy0)*(minX-fx0)/(fx1-fx0)
+		default:
+			x, y = maxX, fy0+(fy1-fy0)*(maxX-fx0)/(fx1-fx0)
+		}
+		x, y = math.Round(x), math.Round(y)
+		if code == code0 {
+			fx0, fy0, code0 = x, y, outcode(x, y)
+		} else {
+			fx1, fy1, code1 = x, y, outcode(x, y)
+		}
+	}
+	return int(fx0), int(fy0), int(fx1), int(fy1), true
+}
+
+// Line - Draw a line between two points (Bresenham)
+func (canvas *Canvas) Line(x0 int, y0 int, x1 int, y1 int, lineColor bool) {
+	x0, y0, x1, y1, ok := canvas.clipLine(x0, y0, x1, y1)
+	if !ok {
+		return
+	}
+	linePoints(x0, y0, x1, y1, func(_, x, y int) {
+		canvas.set(x, y, lineColor)
+	})
+}
+
+// DashedLine - Draw a line of dash pixels on, gap pixels off (dash or gap <= 0 draws it solid).
+// The pattern starts at (x0, y0), also when that end is clipped away.
+func (canvas *Canvas) DashedLine(x0 int, y0 int, x1 int, y1 int, dash int, gap int, lineColor bool) {
+	if dash <= 0 || gap <= 0 {
+		canvas.Line(x0, y0, x1, y1, lineColor)
+		return
+	}
+	cx0, cy0, cx1, cy1, ok := canvas.clipLine(x0, y0, x1, y1)
+	if !ok {
+		return
+	}
+	// Bresenham takes one step per pixel along the major axis
+	skip := absInt(cx0 - x0)
+	if dy := absInt(cy0 - y0); dy > skip {
+		skip = dy
+	}
+	linePoints(cx0, cy0, cx1, cy1, func(i, x, y int) {
+		if (skip+i)%(dash+gap) < dash {
+			canvas.set(x, y, lineColor)
+		}
+	})
+}
+
+// absInt - Absolute value of n
+func absInt(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+// RectOutline - Draw a 1-pixel rectangle frame
+func (canvas *Canvas) RectOutline(MinX int, MinY int, MaxX int, MaxY int, rectColor bool) {
+	canvas.hspan(MinX, MaxX, MinY, rectColor)
+	canvas.hspan(MinX, MaxX, MaxY, rectColor)
+	canvas.Line(MinX, MinY, MinX, MaxY, rectColor)
+	canvas.Line(MaxX, MinY, MaxX, MaxY, rectColor)
+}
+
+// ellipseQuadrant - Midpoint ellipse: points (x, y) of the quadrant x, y >= 0 of an
+// ellipse with radii rx, ry centred on the origin, from (0, ry) to (rx, 0)
+func ellipseQuadrant(rx, ry int, plot func(x, y int)) {
+	if rx <= 0 || ry <= 0 {
+		for x := 0; x <= rx; x++ {
+			plot(x, 0)
+		}
+		for y := 1; y <= ry; y++ {
+			plot(0, y)
+		}
+		return
+	}
+	rx2, ry2 := rx*rx, ry*ry
+	x, y := 0, ry
+	px, py := 0, 2*rx2*y
+
+	// Region 1: slope above -1, step x
+	p := ry2 - rx2*ry + rx2/4
+	for px < py {
+		plot(x, y)
+		x++
+		px += 2 * ry2
+		if p < 0 {
+			p += ry2 + px
+		} else {
+			y--
+			py -= 2 * rx2
+			p += ry2 + px - py
+		}
+	}
+
+	// Region 2: slope below -1, step y
+	p = (ry2*(2*x+1)*(2*x+1))/4 + rx2*(y-1)*(y-1) - rx2*ry2
+	for y >= 0 {
+		plot(x, y)
+		y--
+		py -= 2 * rx2
+		if p > 0 {
+			p += rx2 - py
+		} else {
+			x++
+			px += 2 * ry2
+			p += rx2 - py + px
+		}
+	}
+}
+
+// Ellipse - Draw an ellipse outline centred on (cx, cy)
+func (canvas *Canvas) Ellipse(cx int, cy int, rx int, ry int, lineColor bool) {
+	ellipseQuadrant(rx, ry, func(x, y int) {
+		canvas.set(cx+x, cy+y, lineColor)
+		canvas.set(cx-x, cy+y, lineColor)
+		canvas.set(cx+x, cy-y, lineColor)
+		canvas.set(cx-x, cy-y, lineColor)
+	})
+}
+
+// FillEllipse - Draw a filled ellipse centred on (cx, cy)
+func (canvas *Canvas) FillEllipse(cx int, cy int, rx int, ry int, fillColor bool) {
+	ellipseQuadrant(rx, ry, func(x, y int) {
+		canvas.hspan(cx-x, cx+x, cy+y, fillColor)
+		canvas.hspan(cx-x, cx+x, cy-y, fillColor)
+	})
+}
+
+// Circle - Draw a circle outline of radius r centred on (cx, cy)
+func (canvas *Canvas) Circle(cx int, cy int, r int, lineColor bool) {
+	canvas.Ellipse(cx, cy, r, r, lineColor)
+}
+
+// FillCircle - Draw a filled circle of radius r centred on (cx, cy)
+func (canvas *Canvas) FillCircle(cx int, cy int, r int, fillColor bool) {
+	canvas.FillEllipse(cx, cy, r, r, fillColor)
+}
+
+// Arc - Draw part of a circle outline from startDeg to endDeg. Angles are in degrees,
+// 0 points right and they grow clockwise on screen (90 points down), as for gauges.
+func (canvas *Canvas) Arc(cx int, cy int, r int, startDeg int, endDeg int, lineColor bool) {
+	span := endDeg - startDeg
+	if span < 0 {
+		span = span%360 + 360
+	}
+	if span >= 360 {
+		canvas.Circle(cx, cy, r, lineColor)
+		return
+	}
+	start := float64((startDeg%360 + 360) % 360)
+	plot := func(x, y int) {
+		a := math.Atan2(float64(y), float64(x)) * 180 / math.Pi
+		if math.Mod(a-start+720, 360) <= float64(span) {
+			canvas.set(cx+x, cy+y, lineColor)
+		}
+	}
+	ellipseQuadrant(r, r, func(x, y int) {
+		plot(x, y)
+		plot(-x, y)
+		plot(x, -y)
+		plot(-x, -y)
+	})
+}
+
+// roundRect - Corner centres and clamped radius of a rounded rectangle
+func roundRect(MinX, MinY, MaxX, MaxY, radius int) (l, t, r, b, rad int) {
+	if MinX > MaxX {
+		MinX, MaxX = MaxX, MinX
+	}
+	if MinY > MaxY {
+		MinY, MaxY = MaxY, MinY
+	}
+	rad = radius
+	if w := (MaxX - MinX) / 2; rad > w {
+		rad = w
+	}
+	if h := (MaxY - MinY) / 2; rad > h {
+		rad = h
+	}
+	if rad < 0 {
+		rad = 0
+	}
+	return MinX + rad, MinY + rad, MaxX - rad, MaxY - rad, rad
+}
+
+// RoundRect - Draw a rectangle frame with corners rounded to radius
+func (canvas *Canvas) RoundRect(MinX int, MinY int, MaxX int, MaxY int, radius int, rectColor bool) {
+	l, t, r, b, rad := roundRect(MinX, MinY, MaxX, MaxY, radius)
+	canvas.hspan(l, r, t-rad, rectColor)
+	canvas.hspan(l, r, b+rad, rectColor)
+	canvas.Line(l-rad, t, l-rad, b, rectColor)
+	canvas.Line(r+rad, t, r+rad, b, rectColor)
+	ellipseQuadrant(rad, rad, func(x, y int) {
+		canvas.set(r+x, b+y, rectColor)
+		canvas.set(l-x, b+y, rectColor)
+		canvas.set(r+x, t-y, rectColor)
+		canvas.set(l-x, t-y, rectColor)
+	})
+}
+
+// FillRoundRect - Draw a filled rectangle with corners rounded to radius
+func (canvas *Canvas) FillRoundRect(MinX int, MinY int, MaxX int, MaxY int, radius int, rectColor bool) {
+	l, t, r, b, rad := roundRect(MinX, MinY, MaxX, MaxY, radius)
+	for y := t; y <= b; y++ {
+		canvas.hspan(l-rad, r+rad, y, rectColor)
+	}
+	ellipseQuadrant(rad, rad, func(x, y int) {
+		canvas.hspan(l-x, r+x, t-y, rectColor)
+		canvas.hspan(l-x, r+x, b+y, rectColor)
+	})
+}
+
+// Polygon - Draw a closed polygon outline through points
+func (canvas *Canvas) Polygon(points []image.Point, lineColor bool) {
+	for i, p := range points {
+		q := points[(i+1)%len(points)]
+		canvas.Line(p.X, p.Y, q.X, q.Y, lineColor)
+	}
+}
+
+// FillPolygon - Draw a filled polygon (even-odd rule), edges included
+func (canvas *Canvas) FillPolygon(points []image.Point, fillColor bool) {
+	if len(points) == 0 {
+		return
+	}
+	minY, maxY := points[0].Y, points[0].Y
+	for _, p := range points {
+		if p.Y < minY {
+			minY = p.Y
+		}
+		if p.Y > maxY {
+			maxY = p.Y
+		}
+	}
+	b := canvas.bounds()
+	if minY < b.Min.Y {
+		minY = b.Min.Y
+	}
+	if maxY >= b.Max.Y {
+		maxY = b.Max.Y - 1
+	}
+
+	var xs []int
+	for y := minY; y <= maxY; y++ {
+		xs = xs[:0]
+		for i, p := range points {
+			q := points[(i+1)%len(points)]
+			if p.Y == q.Y {
+				continue
+			}
+			if p.Y > q.Y {
+				p, q = q, p
+			}
+			// Half-open so a vertex shared by two edges counts once
+			if y < p.Y || y >= q.Y {
+				continue
+			}
+			x := float64(p.X) + float64((y-p.Y)*(q.X-p.X))/float64(q.Y-p.Y)
+			xs = append(xs, int(math.Round(x)))
+		}
+		sort.Ints(xs)
+		for i := 0; i+1 < len(xs); i += 2 {
+			canvas.hspan(xs[i], xs[i+1], y, fillColor)
+		}
+	}
+	canvas.Polygon(points, fillColor)
+}
+
+// Triangle - Draw a triangle outline
+func (canvas *Canvas) Triangle(x0 int, y0 int, x1 int, y1 int, x2 int, y2 int, lineColor bool) {
+	canvas.Polygon([]image.Point{{X: x0, Y: y0}, {X: x1, Y: y1}, {X: x2, Y: y2}}, lineColor)
+}
+
+// FillTriangle - Draw a filled triangle
+func (canvas *Canvas) FillTriangle(x0 int, y0 int, x1 int, y1 int, x2 int, y2 int, fillColor bool) {
+	canvas.FillPolygon([]image.Point{{X: x0, Y: y0}, {X: x1, Y: y1}, {X: x2, Y: y2}}, fillColor)
+}
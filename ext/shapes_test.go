@@ -0,0 +1,119 @@
+package nanohatoled
+
+import (
+	"image"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// testCanvas - Blank off-screen w x h canvas
+func testCanvas(t *testing.T, w, h int) *Canvas {
+	t.Helper()
+	oled, _ := testOled(t, SSD1306)
+	return oled.NewCanvas(w, h)
+}
+
+// rows - Canvas pixels as strings, '#' lit and '.' dark
+func rows(canvas *Canvas) []string {
+	w, h := canvas.Size()
+	out := make([]string, h)
+	for y := range out {
+		var b strings.Builder
+		for x := 0; x < w; x++ {
+			if canvas.get(x, y) {
+				b.WriteByte('#')
+			} else {
+				b.WriteByte('.')
+			}
+		}
+		out[y] = b.String()
+	}
+	return out
+}
+
+func checkRows(t *testing.T, name string, canvas *Canvas, want ...string) {
+	t.Helper()
+	if got := rows(canvas); !reflect.DeepEqual(got, want) {
+		t.Errorf("%s:\ngot\n%s\nwant\n%s", name, strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestLineClipped(t *testing.T) {
+	c := testCanvas(t, 6, 4)
+	c.Line(-1000000, 1, 1000000, 1, true)
+	c.Line(-1000000, -1000000, 1000000, 1000000, true)
+	c.Line(-5, 3, -1, 3, true)       // Wholly off-screen
+	c.Line(3, -1000000, 3, -1, true) // Wholly off-screen
+	c.Line(5, 1000000, 5, 3, true)   // One end inside
+	checkRows(t, "clipped lines", c,
+		"#.....",
+		"######",
+		"..#...",
+		"...#.#")
+
+	c = testCanvas(t, 6, 4)
+	c.Line(0, 0, 5, 3, true)
+	c.Line(-10, 3, 15, 3, false) // Clear the bottom row again
+	checkRows(t, "visible line", c,
+		"#.....",
+		".##...",
+		"...##.",
+		"......")
+}
+
+func TestDashedLine(t *testing.T) {
+	c := testCanvas(t, 10, 3)
+	c.DashedLine(0, 0, 9, 0, 2, 1, true)
+	c.DashedLine(-3, 1, 9, 1, 2, 1, true)       // Clipped start keeps the phase
+	c.DashedLine(-1000001, 2, 9, 2, 2, 1, true) // Phase of a far away start
+	c.DashedLine(0, 2, 9, 2, 0, 1, false)       // No dash draws solid
+	checkRows(t, "dashes", c,
+		"##.##.##.#",
+		"##.##.##.#",
+		"..........")
+
+	c = testCanvas(t, 10, 1)
+	c.DashedLine(-1000001, 0, 9, 0, 2, 1, true)
+	checkRows(t, "far start", c, ".##.##.##.") // 1000001 steps before x = 0 is 2 into a period
+}
+
+func TestArc(t *testing.T) {
+	c := testCanvas(t, 7, 7)
+	c.Arc(3, 3, 3, 0, 90, true) // Right to down, clockwise on screen
+	checkRows(t, "quarter arc", c,
+		".......",
+		".......",
+		".......",
+		"......#",
+		"......#",
+		".....#.",
+		"...##..")
+
+	c = testCanvas(t, 7, 7)
+	c.Arc(3, 3, 3, 180, 540, true) // Whole circle
+	c.Circle(3, 3, 3, false)
+	checkRows(t, "full arc", c, ".......", ".......", ".......", ".......", ".......", ".......", ".......")
+}
+
+func TestFillPolygon(t *testing.T) {
+	c := testCanvas(t, 7, 6)
+	c.FillPolygon([]image.Point{{1, 0}, {5, 0}, {5, 2}, {1, 2}}, true)
+	c.FillTriangle(0, 3, 4, 3, 0, 5, true)
+	checkRows(t, "filled polygons", c,
+		".#####.",
+		".#####.",
+		".#####.",
+		"#####..",
+		"####...", // Outline steps past the span
+		"##.....")
+
+	// Concave U shape, even-odd leaves the notch empty
+	c = testCanvas(t, 5, 4)
+	c.FillPolygon([]image.Point{{0, 0}, {1, 0}, {1, 2}, {3, 2}, {3, 0}, {4, 0}, {4, 3}, {0, 3}}, true)
+	checkRows(t, "concave", c,
+		"##.##",
+		"##.##",
+		"#####",
+		"#####")
+}
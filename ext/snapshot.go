@@ -0,0 +1,94 @@
+package nanohatoled
+
+import (
+	"bufio"
+	"fmt"
+	"image"
+	"image/png"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// snapshot - Last sent frame scaled up by an integer factor (nearest neighbour)
+func (nanoOled *NanoOled) snapshot(scale int) *image.Gray {
+	if scale < 1 {
+		scale = 1
+	}
+	frame := nanoOled.Frame()
+	if scale == 1 {
+		return frame
+	}
+
+	w, h := frame.Rect.Dx(), frame.Rect.Dy()
+	scaled := image.NewGray(image.Rect(0, 0, w*scale, h*scale))
+	for y := 0; y < h*scale; y++ {
+		src := frame.Pix[(y/scale)*frame.Stride:]
+		dst := scaled.Pix[y*scaled.Stride:]
+		for x := 0; x < w*scale; x++ {
+			dst[x] = src[x/scale]
+		}
+	}
+	return scaled
+}
+
+// WritePNG - Encode the last sent frame as PNG, each panel pixel becomes scale x scale pixels
+func (nanoOled *NanoOled) WritePNG(w io.Writer, scale int) error {
+	if err := png.Encode(w, nanoOled.snapshot(scale)); err != nil {
+		return fmt.Errorf("encode PNG failed: %w", err)
+	}
+	return nil
+}
+
+// WritePBM - Encode the last sent frame as binary 1-bit PBM (P4), lit pixels are white
+func (nanoOled *NanoOled) WritePBM(w io.Writer, scale int) error {
+	img := nanoOled.snapshot(scale)
+	width, height := img.Rect.Dx(), img.Rect.Dy()
+
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "P4\n%d %d\n", width, height)
+	row := make([]byte, (width+7)/8)
+	for y := 0; y < height; y++ {
+		for i := range row {
+			row[i] = 0
+		}
+		src := img.Pix[y*img.Stride:]
+		for x := 0; x < width; x++ {
+			// PBM uses 1 for black, so unlit pixels set the bit
+			if src[x] == 0 {
+				row[x/8] |= 0x80 >> uint(x%8)
+			}
+		}
+		if _, err := bw.Write(row); err != nil {
+			return fmt.Errorf("write PBM failed: %w", err)
+		}
+	}
+	if err := bw.Flush(); err != nil {
+		return fmt.Errorf("write PBM failed: %w", err)
+	}
+	return nil
+}
+
+// SaveSnapshot - Write the last sent frame to path, format chosen by extension (.png or .pbm)
+func (nanoOled *NanoOled) SaveSnapshot(path string, scale int) error {
+	var write func(io.Writer, int) error
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".png":
+		write = nanoOled.WritePNG
+	case ".pbm":
+		write = nanoOled.WritePBM
+	default:
+		return fmt.Errorf("unsupported snapshot format: %s", path)
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("create snapshot failed: %w", err)
+	}
+	if err := write(f, scale); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,120 @@
+package nanohatoled
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"image"
+	"image/png"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// decodePBM - Decode a binary PBM, lit (white) pixels as 0xff
+func decodePBM(r io.Reader) (*image.Gray, error) {
+	br := bufio.NewReader(r)
+	var w, h int
+	if _, err := fmt.Fscanf(br, "P4\n%d %d\n", &w, &h); err != nil {
+		return nil, err
+	}
+	img := image.NewGray(image.Rect(0, 0, w, h))
+	row := make([]byte, (w+7)/8)
+	for y := 0; y < h; y++ {
+		if _, err := io.ReadFull(br, row); err != nil {
+			return nil, err
+		}
+		for x := 0; x < w; x++ {
+			if row[x/8]&(0x80>>uint(x%8)) == 0 {
+				img.Pix[y*img.Stride+x] = 0xff
+			}
+		}
+	}
+	return img, nil
+}
+
+// snapshotPattern - Lit pixels drawn into the frame, an L shape with a stray dot
+var snapshotPattern = []image.Point{{0, 0}, {1, 0}, {2, 0}, {0, 1}, {0, 2}, {100, 40}, {127, 63}}
+
+func TestSnapshotRoundTrip(t *testing.T) {
+	cases := []struct {
+		name     string
+		rotation int
+		invert   bool
+		panel    func(p image.Point) image.Point // Where a logical pixel lights up
+	}{
+		{"upright", 0, false, func(p image.Point) image.Point { return p }},
+		{"upside down", 180, false, func(p image.Point) image.Point { return image.Pt(127-p.X, 63-p.Y) }},
+		{"inverted", 0, true, func(p image.Point) image.Point { return p }},
+	}
+	const scale = 2
+	for _, c := range cases {
+		oled, _ := testOled(t, SSD1306)
+		oled.New(c.rotation)
+		if err := oled.SetInvert(c.invert); err != nil {
+			t.Fatal(err)
+		}
+		want := map[image.Point]bool{}
+		for _, p := range snapshotPattern {
+			oled.Pixel(p.X, p.Y, true)
+			want[c.panel(p)] = true
+		}
+		if err := oled.Send(); err != nil {
+			t.Fatal(err)
+		}
+
+		var pngBuf, pbmBuf bytes.Buffer
+		if err := oled.WritePNG(&pngBuf, scale); err != nil {
+			t.Fatal(err)
+		}
+		if err := oled.WritePBM(&pbmBuf, scale); err != nil {
+			t.Fatal(err)
+		}
+		fromPNG, err := png.Decode(&pngBuf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		fromPBM, err := decodePBM(&pbmBuf)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for format, img := range map[string]image.Image{"PNG": fromPNG, "PBM": fromPBM} {
+			if size := img.Bounds().Size(); size != image.Pt(128*scale, 64*scale) {
+				t.Errorf("%s %s: size %v", c.name, format, size)
+				continue
+			}
+			bad := 0
+			for y := 0; y < 64*scale; y++ {
+				for x := 0; x < 128*scale; x++ {
+					r, _, _, _ := img.At(x, y).RGBA()
+					lit := r > 0x8000
+					if lit != (want[image.Pt(x/scale, y/scale)] != c.invert) {
+						bad++
+					}
+				}
+			}
+			if bad > 0 {
+				t.Errorf("%s %s: %d pixels differ", c.name, format, bad)
+			}
+		}
+	}
+}
+
+func TestSaveSnapshot(t *testing.T) {
+	oled, _ := testOled(t, SSD1306)
+	dir := t.TempDir()
+	for _, name := range []string{"shot.png", "shot.PBM"} {
+		path := filepath.Join(dir, name)
+		if err := oled.SaveSnapshot(path, 1); err != nil {
+			t.Fatal(err)
+		}
+		if fi, err := os.Stat(path); err != nil || fi.Size() == 0 {
+			t.Errorf("%s: %v", name, err)
+		}
+	}
+	if err := oled.SaveSnapshot(filepath.Join(dir, "shot.jpg"), 1); err == nil {
+		t.Error("jpg accepted")
+	}
+}
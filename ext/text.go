@@ -0,0 +1,250 @@
+package nanohatoled
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/math/fixed"
+)
+
+// Align - Horizontal text alignment inside a box
+type Align int
+
+const (
+	AlignLeft Align = iota
+	AlignCenter
+	AlignRight
+)
+
+// TextOptions - Layout settings for TextBox
+type TextOptions struct {
+	Align       Align
+	Wrap        bool // Break lines at spaces (or anywhere for long words) to fit the box width
+	Ellipsis    bool // End text that does not fit with "…"
+	LineSpacing int  // Extra pixels between lines
+}
+
+const ellipsis = "…"
+
+// face - Font face for the current font and size, built once and cached
+func (canvas *Canvas) face() *cachedFace {
+	var primary Font = trueTypeFont{canvas.currentFont}
+	if canvas.font != nil {
+		primary = canvas.font
+	}
+	key := faceKey{font: primary, size: canvas.fontSize, bold: canvas.bold}
+	if face, ok := canvas.res.faces[key]; ok {
+		return face
+	}
+
+	f := primary
+	if len(canvas.res.fallback) > 0 {
+		f = &fallbackFont{fonts: append([]Font{primary}, canvas.res.fallback...)}
+	}
+	if canvas.res.faces == nil || len(canvas.res.faces) >= maxCachedFaces {
+		canvas.res.faces = map[faceKey]*cachedFace{}
+	}
+	face := newCachedFace(f.face(canvas.fontSize, canvas.bold))
+	canvas.res.faces[key] = face
+	return face
+}
+
+// baseline - Baseline for text whose top edge is at y (offset adapts to vertical spacing)
+func (canvas *Canvas) baseline(y int) int {
+	if canvas.font != nil {
+		return y + canvas.face().Metrics().Ascent.Ceil()
+	}
+	var baselineOffset int
+	switch {
+	case canvas.fontSize <= 12:
+		baselineOffset = 2 // Small font offset
+	case canvas.fontSize <= 24:
+		baselineOffset = 3 // Medium font offset
+	default:
+		baselineOffset = 4 // Large font offset
+	}
+	return y + int(canvas.fontSize*FixedDPI/72) - baselineOffset
+}
+
+// drawString - Render text with its baseline origin at (x, baseY), lit or unlit.
+// Cached glyph masks are blitted straight into the buffer: anti-aliased pixels change
+// exactly as when compositing over the frame with image/draw and the image Threshold.
+func (canvas *Canvas) drawString(x, baseY int, text string, on bool) {
+	face := canvas.face()
+	thresh := uint32(canvas.image.Threshold)
+	dot := fixed.P(x, baseY)
+	prev := rune(-1)
+	for _, r := range text {
+		if prev >= 0 {
+			dot.X += face.Kern(prev, r)
+		}
+		g := face.glyph(dot, r)
+		if !g.ok {
+			continue
+		}
+		x0, y0 := dot.X.Floor()+g.off.X, dot.Y.Floor()+g.off.Y
+		m := g.mask
+		for my := 0; my < m.Rect.Dy(); my++ {
+			for mx, a8 := range m.Pix[my*m.Stride : my*m.Stride+m.Rect.Dx()] {
+				if a8 == 0 {
+					continue
+				}
+				a := uint32(a8) * 0x101
+				if on && a > thresh {
+					canvas.set(x0+mx, y0+my, true)
+				} else if !on && 0xffff-a <= thresh {
+					canvas.set(x0+mx, y0+my, false)
+				}
+			}
+		}
+		dot.X += g.advance
+		prev = r
+	}
+}
+
+// MeasureText - Width of text in pixels and the line height of the current font
+func (canvas *Canvas) MeasureText(text string) (int, int) {
+	face := canvas.face()
+	return font.MeasureString(face, text).Ceil(), face.Metrics().Height.Ceil()
+}
+
+// ellipsisFor - Ellipsis string the face can render
+func ellipsisFor(face font.Face) string {
+	r, _ := utf8.DecodeRuneInString(ellipsis)
+	if _, ok := face.GlyphAdvance(r); ok {
+		return ellipsis
+	}
+	return "..."
+}
+
+// fitWidth - Longest prefix of text (in runes) not wider than maxWidth
+func fitWidth(face font.Face, text string, maxWidth fixed.Int26_6) string {
+	var width fixed.Int26_6
+	prev := rune(-1)
+	for i, r := range text {
+		if prev >= 0 {
+			width += face.Kern(prev, r)
+		}
+		adv, _ := face.GlyphAdvance(r)
+		width += adv
+		if width > maxWidth {
+			return text[:i]
+		}
+		prev = r
+	}
+	return text
+}
+
+// truncate - Shorten text to maxWidth pixels, ending with an ellipsis when something was cut
+func truncate(face font.Face, text string, maxWidth int, withEllipsis bool) string {
+	limit := fixed.I(maxWidth)
+	if font.MeasureString(face, text) <= limit {
+		return text
+	}
+	if !withEllipsis {
+		return fitWidth(face, text, limit)
+	}
+	dots := ellipsisFor(face)
+	dotsWidth := font.MeasureString(face, dots)
+	if dotsWidth > limit {
+		return fitWidth(face, text, limit) // No room for the ellipsis itself
+	}
+	return fitWidth(face, text, limit-dotsWidth) + dots
+}
+
+// FitText - Shorten text with an ellipsis so it is at most maxWidth pixels wide in the current font
+func (canvas *Canvas) FitText(text string, maxWidth int) string {
+	return truncate(canvas.face(), text, maxWidth, true)
+}
+
+// wrap - Split text into lines no wider than maxWidth, breaking at spaces where possible
+func wrap(face font.Face, text string, maxWidth int) []string {
+	limit := fixed.I(maxWidth)
+	var lines []string
+	for _, para := range strings.Split(text, "\n") {
+		line := ""
+		for _, word := range strings.Fields(para) {
+			candidate := word
+			if line != "" {
+				candidate = line + " " + word
+			}
+			if font.MeasureString(face, candidate) <= limit {
+				line = candidate
+				continue
+			}
+			if line != "" {
+				lines = append(lines, line)
+			}
+			// Words wider than the box (IPv6 addresses, long hostnames) break anywhere
+			for font.MeasureString(face, word) > limit {
+				head := fitWidth(face, word, limit)
+				if head == "" {
+					_, size := utf8.DecodeRuneInString(word)
+					head = word[:size]
+				}
+				lines = append(lines, head)
+				word = word[len(head):]
+			}
+			line = word
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// WrapText - Split text into lines that fit maxWidth pixels in the current font
+func (canvas *Canvas) WrapText(text string, maxWidth int) []string {
+	return wrap(canvas.face(), text, maxWidth)
+}
+
+// TextBox - Draw text inside the box at (x, y) of size w x h, aligned, wrapped and
+// truncated as set in opts. Lines that do not fit the box height are dropped, the last
+// visible line gets an ellipsis when opts.Ellipsis is set.
+func (canvas *Canvas) TextBox(x int, y int, w int, h int, text string, opts TextOptions, textColor bool) {
+	if w <= 0 || h <= 0 {
+		return
+	}
+	face := canvas.face()
+	lineHeight := face.Metrics().Height.Ceil() + opts.LineSpacing
+	if lineHeight < 1 {
+		lineHeight = 1 // Negative spacing or a font without height
+	}
+
+	var lines []string
+	if opts.Wrap {
+		lines = wrap(face, text, w)
+	} else {
+		lines = strings.Split(text, "\n")
+	}
+
+	maxLines := h / lineHeight
+	if maxLines < 1 {
+		maxLines = 1
+	}
+	if len(lines) > maxLines {
+		lines = lines[:maxLines]
+		if opts.Ellipsis {
+			// Mark the cut even if the last kept line is short
+			dots := ellipsisFor(face)
+			last := lines[maxLines-1] + dots
+			if font.MeasureString(face, last) > fixed.I(w) {
+				last = fitWidth(face, lines[maxLines-1], fixed.I(w)-font.MeasureString(face, dots)) + dots
+			}
+			lines[maxLines-1] = last
+		}
+	}
+
+	canvas.image.Threshold = canvas.getDynamicThreshold()
+	for i, line := range lines {
+		line = truncate(face, line, w, opts.Ellipsis)
+		lineX := x
+		switch opts.Align {
+		case AlignCenter:
+			lineX = x + (w-font.MeasureString(face, line).Ceil())/2
+		case AlignRight:
+			lineX = x + w - font.MeasureString(face, line).Ceil()
+		}
+		canvas.drawString(lineX, canvas.baseline(y+i*lineHeight), line, textColor)
+	}
+}
@@ -0,0 +1,124 @@
+package nanohatoled
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// litColumns - Leftmost and rightmost lit column of the canvas, -1 when nothing is lit
+func litColumns(canvas *Canvas) (int, int) {
+	w, h := canvas.Size()
+	left, right := -1, -1
+	for x := 0; x < w; x++ {
+		for y := 0; y < h; y++ {
+			if canvas.get(x, y) {
+				if left < 0 {
+					left = x
+				}
+				right = x
+				break
+			}
+		}
+	}
+	return left, right
+}
+
+func TestMeasureText(t *testing.T) {
+	c := testCanvas(t, 128, 64)
+	c.SetFontSize(12)
+	w1, h := c.MeasureText("0")
+	w8, h8 := c.MeasureText("00:00:00")
+	if w1 <= 0 || h <= 0 || h8 != h {
+		t.Fatalf("sizes %dx%d and %dx%d", w1, h, w8, h8)
+	}
+	// Monospace: eight advances, rounded up once
+	if w8 < 8*w1-8 || w8 > 8*w1 {
+		t.Errorf("8 runes %d wide, one %d", w8, w1)
+	}
+	if w, _ := c.MeasureText(""); w != 0 {
+		t.Errorf("empty text %d wide", w)
+	}
+}
+
+func TestTextBoxAlign(t *testing.T) {
+	const boxW = 60
+	draw := func(align Align) *Canvas {
+		c := testCanvas(t, 128, 20)
+		c.SetFontSize(12)
+		c.TextBox(10, 0, boxW, 20, "IP", TextOptions{Align: align}, true)
+		return c
+	}
+	left, _ := litColumns(draw(AlignLeft))
+	width, _ := draw(AlignLeft).MeasureText("IP")
+
+	// Glyphs sit at integer positions, so alignment shifts the same pixels
+	for align, shift := range map[Align]int{AlignCenter: (boxW - width) / 2, AlignRight: boxW - width} {
+		c := draw(align)
+		got, _ := litColumns(c)
+		if got != left+shift {
+			t.Errorf("align %d: first lit column %d, want %d", align, got, left+shift)
+		}
+		shifted := testCanvas(t, 128, 20)
+		shifted.SetFontSize(12)
+		shifted.TextBox(10+shift, 0, boxW, 20, "IP", TextOptions{}, true)
+		if !reflect.DeepEqual(rows(c), rows(shifted)) {
+			t.Errorf("align %d differs from left alignment shifted by %d", align, shift)
+		}
+	}
+}
+
+func TestWrapLongWord(t *testing.T) {
+	c := testCanvas(t, 128, 64)
+	c.SetFontSize(11)
+	const ip = "2001:db8:85a3::8a2e:370:7334"
+	lines := c.WrapText("IPv6 "+ip, 60)
+	if len(lines) < 3 || lines[0] != "IPv6" {
+		t.Fatalf("lines %q", lines)
+	}
+	for _, line := range lines {
+		if w, _ := c.MeasureText(line); w > 60 {
+			t.Errorf("line %q is %d wide", line, w)
+		}
+	}
+	if got := strings.Join(lines[1:], ""); got != ip {
+		t.Errorf("address split into %q", lines[1:])
+	}
+}
+
+func TestEllipsisFitsBox(t *testing.T) {
+	c := testCanvas(t, 128, 64)
+	c.SetFontSize(12)
+	const text = "eth0 192.168.100.200 up 1000Mb/s full duplex"
+	for w := 1; w <= 80; w += 3 {
+		got := c.FitText(text, w)
+		if gw, _ := c.MeasureText(got); gw > w {
+			t.Errorf("FitText(%d) = %q, %d wide", w, got, gw)
+		}
+
+		box := testCanvas(t, 128, 64)
+		box.SetFontSize(12)
+		box.TextBox(20, 0, w, 30, text, TextOptions{Wrap: true, Ellipsis: true}, true)
+		box.TextBox(20, 32, w, 30, text, TextOptions{Ellipsis: true, Align: AlignRight}, true)
+		if left, right := litColumns(box); left >= 0 && (left < 20 || right >= 20+w) {
+			t.Errorf("box %d wide: text lit columns %d-%d", w, left, right)
+		}
+	}
+}
+
+func TestTextBoxZeroLineHeight(t *testing.T) {
+	c := testCanvas(t, 128, 64)
+	height := c.face().Metrics().Height.Ceil()
+
+	// Lines one pixel apart, as if drawn one by one
+	want := testCanvas(t, 128, 64)
+	want.TextBox(0, 0, 128, 64, "ab", TextOptions{}, true)
+	want.TextBox(0, 1, 128, 64, "cd", TextOptions{}, true)
+	for _, spacing := range []int{-height, -height - 5} {
+		got := testCanvas(t, 128, 64)
+		got.TextBox(0, 0, 128, 64, "ab\ncd", TextOptions{LineSpacing: spacing}, true)
+		if !reflect.DeepEqual(rows(got), rows(want)) {
+			t.Errorf("spacing %d: lines not 1 pixel apart", spacing)
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package nanohatoled
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+)
+
+// virtualBus - In-memory stand-in for the I2C device, accepts and discards every write
+type virtualBus struct{}
+
+func (virtualBus) Write(buf []byte) error { return nil }
+
+func (virtualBus) Close() error { return nil }
+
+// OpenVirtual - Create a display without hardware, the frame only lives in memory.
+// Geometry and font directory come from opts, bus and button settings are ignored.
+func OpenVirtual(opts Options) (*NanoOled, error) {
+	opts.Address = DefaultOptions().Address
+	opts.NoButtons = true
+	if err := opts.validate(); err != nil {
+		return nil, err
+	}
+	oled, err := newNanoOled(virtualBus{}, opts)
+	if err != nil {
+		return nil, err
+	}
+	if err := oled.init(); err != nil {
+		return nil, fmt.Errorf("OLED init failed: %w", err)
+	}
+	return oled, nil
+}
+
+// IsVirtual - Report whether the display runs without a panel attached
+func (nanoOled *NanoOled) IsVirtual() bool {
+	_, ok := nanoOled.dev.(virtualBus)
+	return ok
+}
+
+// Frame - Decode the last frame sent to the panel into a grayscale image, as the panel
+// lights it when looked at in its unrotated mounting: hardware inversion is applied and
+// a controller flipped for rotation 180 or 270 turns the frame upside down
+func (nanoOled *NanoOled) Frame() *image.Gray {
+	frame := image.NewGray(image.Rect(0, 0, nanoOled.w, nanoOled.h))
+	for y := 0; y < nanoOled.h; y++ {
+		for x := 0; x < nanoOled.w; x++ {
+			sx, sy := x, y
+			if nanoOled.hwFlipped {
+				sx, sy = nanoOled.w-1-x, nanoOled.h-1-y
+			}
+			lit := nanoOled.buf[1+sx+(sy/8)*nanoOled.w]&(1<<uint(sy&7)) != 0
+			if lit != nanoOled.inverted {
+				frame.SetGray(x, y, color.Gray{Y: 0xff})
+			}
+		}
+	}
+	return frame
+}
@@ -2,13 +2,17 @@ package main
 
 import (
 	"bufio"
+	"context"
+	"errors"
 	"fmt"
+	"image"
 	"io"
 	"io/ioutil"
 	"log"
 	"math"
 	"net"
 	"os"
+	"os/signal"
 	"strconv"
 	"strings"
 	"sync"
@@ -24,21 +28,30 @@ const (
 	logFilePath   = "/tmp/nanohat-oled.log"
 	pidFilePath   = "/var/run/nanohat-oled.pid"
 	logoPath      = "/etc/NanoHatOLED/logo.png"
+	logoAnimPath  = "/etc/NanoHatOLED/logo.gif"
+	snapshotPath  = "/tmp/nanohat-oled.png"
+	snapshotScale = 4
+	spinnerSize   = 16
 	pageSleep     = 10
-	displayWidth  = 128
-	displayHeight = 64
 	btnK1         = 0
 	btnK2         = 1
 	btnK3         = 2
-	timeX         = 8
 	timeY         = 38
 	timeWidth     = 110
 	timeHeight    = 28
+
+	splashDuration    = 2 * time.Second
+	splashMaxDuration = 10 * time.Second
+	probeInterval     = 5 // Seconds between looks for a missing panel
 )
 
 var (
 	logger          *LocalTimeLogger
-	oled            *nanohatoled.NanoOled
+	cfg             *config
+	displayWidth    int
+	displayHeight   int
+	oled            nanohatoled.Display
+	infoFont        nanohatoled.Font // System-info page font, nil uses DejaVu
 	pageIndex       int
 	pageSleepCount  int
 	drawing         bool
@@ -49,9 +62,19 @@ var (
 	lastTimeStr     string
 	lastShutdownSel int
 	staticDrawn     bool
+	sendFailing     bool // Last Send failed, see sendFrame
 	localLoc        *time.Location
+	dayContrast     int
+	appliedContrast int
+	timeX           int
+	timeShift       image.Point         // Burn-in offset the clock page was drawn with
+	timePage        *nanohatoled.Canvas // Clock page viewport, moved by timeShift
+	idleDimmed      bool
 )
 
+// shiftOrbit is the path the clock page walks against burn-in, in units of shift_pixels
+var shiftOrbit = []image.Point{{0, 0}, {1, 0}, {1, 1}, {0, 1}, {-1, 1}, {-1, 0}, {-1, -1}, {0, -1}, {1, -1}}
+
 // executeDateCommand runs date command with specified argument via syscall.Exec
 func executeDateCommand(arg string) (string, error) {
 	readPipe, writePipe, err := os.Pipe()
@@ -419,6 +442,31 @@ func getCPUTemp() string {
 	return fmt.Sprintf("CPU TEMP: %d°C", temp)
 }
 
+// getUptime returns system uptime as days, hours and minutes
+func getUptime() string {
+	data, err := ioutil.ReadFile("/proc/uptime")
+	if err != nil {
+		logger.Printf("Read uptime failed: %v", err)
+		return "Up: N/A"
+	}
+	fields := strings.Fields(string(data))
+	if len(fields) == 0 {
+		return "Up: N/A"
+	}
+	secs, _ := strconv.ParseFloat(fields[0], 64)
+	mins := int(secs) / 60
+	return fmt.Sprintf("Up: %dd %dh %dm", mins/1440, mins/60%24, mins%60)
+}
+
+// getHostname returns the system host name
+func getHostname() string {
+	name, err := os.Hostname()
+	if err != nil {
+		return "Host: N/A"
+	}
+	return "Host: " + name
+}
+
 // getYearProgressText returns year progress bar + percentage
 func getYearProgressText() string {
 	now := time.Now().In(localLoc)
@@ -438,30 +486,42 @@ func getYearProgressText() string {
 
 // clearTimeArea clears time display region on OLED
 func clearTimeArea() {
-	oled.Rect(timeX-1, timeY+2, timeX+timeWidth+10, timeY+timeHeight, false)
-	oled.SetFontSize(24)
-	oled.SetBold(true)
-	oled.Text(timeX, timeY, "               ", false)
+	timePage.Rect(timeX-1, timeY+2, timeX+timeWidth+10, timeY+timeHeight, false)
+	timePage.SetFontSize(24)
+	timePage.SetBold(true)
+	timePage.Text(timeX, timeY, "               ", false)
+}
+
+// clockShift returns the burn-in offset of the clock page at now
+func clockShift(now time.Time) image.Point {
+	if cfg.shiftPixels <= 0 || cfg.shiftInterval <= 0 {
+		return image.Point{}
+	}
+	step := now.UnixNano() / int64(cfg.shiftInterval)
+	return shiftOrbit[step%int64(len(shiftOrbit))].Mul(cfg.shiftPixels)
 }
 
 // drawTimePageStatic draws static elements of time page
 func drawTimePageStatic() {
-	oled.Clear()
-	oled.New(0)
+	oled.Fill(false)
+	timeShift = clockShift(time.Now())
+	timePage = oled.Viewport(image.Rect(0, 0, displayWidth, displayHeight).Add(timeShift))
 
-	oled.SetFontSize(14)
-	oled.SetBold(false)
-	oled.Text(2, 2, time.Now().In(localLoc).Format("Mon _2 Jan 2006"), true)
-	oled.Text(2, 20, getYearProgressText(), true)
+	timePage.SetFontSize(14)
+	timePage.SetBold(false)
+	timePage.Text(2, 2, time.Now().In(localLoc).Format("Mon _2 Jan 2006"), true)
+	timePage.Text(2, 20, getYearProgressText(), true)
 
-	oled.SetFontSize(24)
-	oled.SetBold(true)
+	timePage.SetFontSize(24)
+	timePage.SetBold(true)
 	currentTime := time.Now().In(localLoc).Format("15:04:05")
-	oled.Text(timeX, timeY, currentTime, true)
+	timeW, _ := timePage.MeasureText(currentTime)
+	timeX = (displayWidth - timeW) / 2
+	timePage.Text(timeX, timeY, currentTime, true)
 
-	oled.Send()
 	lastTimeStr = currentTime
 	staticDrawn = true
+	sendFrame()
 }
 
 // updateTimeOnly refreshes time value (second-level update)
@@ -472,26 +532,76 @@ func updateTimeOnly() {
 	}
 
 	clearTimeArea()
-	oled.Text(timeX, timeY, currentTime, true)
-	oled.Send()
-
+	timePage.Text(timeX, timeY, currentTime, true)
 	lastTimeStr = currentTime
+	sendFrame()
+}
+
+// loadInfoFont loads the configured system-info font, keeping DejaVu on failure
+func loadInfoFont() {
+	if cfg.infoFont == "" {
+		return
+	}
+	f, err := nanohatoled.LoadFont(cfg.infoFont)
+	if err != nil {
+		logger.Printf("Info font load failed, using default: %v", err)
+		return
+	}
+	infoFont = f
+}
+
+// infoIconSize returns the largest built-in icon size fitting a line, 0 if none
+func infoIconSize(lineHeight int) int {
+	best := 0
+	for _, size := range nanohatoled.IconSizes {
+		if size <= lineHeight {
+			best = size
+		}
+	}
+	return best
 }
 
 // drawNonTimePage draws system info/shutdown pages
 func drawNonTimePage() {
-	oled.Clear()
-	oled.New(0)
+	oled.Fill(false)
 
 	switch pageIndex {
 	case 1:
 		oled.SetFontSize(10)
 		oled.SetBold(false)
-		oled.Text(2, 0, "IP: "+getIP(), true)
-		oled.Text(2, 12, getCPULoad(), true)
-		oled.Text(2, 24, getMemUsage(), true)
-		oled.Text(2, 36, getDiskUsage(), true)
-		oled.Text(2, 48, getCPUTemp(), true)
+		lineHeight := 12
+		if infoFont != nil {
+			oled.SetFont(infoFont)
+			_, lineHeight = oled.MeasureText("")
+		}
+		// Smaller bitmap fonts leave room for the trailing lines
+		lines := []struct{ icon, text string }{
+			{"ethernet", "IP: " + getIP()},
+			{"cpu", getCPULoad()},
+			{"memory", getMemUsage()},
+			{"disk", getDiskUsage()},
+			{"thermometer", getCPUTemp()},
+			{"clock", getUptime()},
+			{"", getHostname()},
+		}
+		line := nanohatoled.TextOptions{Ellipsis: true}
+		for i, l := range lines {
+			y := i * lineHeight
+			if y+lineHeight > displayHeight {
+				break
+			}
+			if size := infoIconSize(lineHeight); cfg.infoIcons && l.icon != "" && size > 0 {
+				// The icon replaces the "Label: " prefix
+				oled.DrawIcon(2, y+(lineHeight-size)/2, l.icon, size, true)
+				if _, value, ok := strings.Cut(l.text, ": "); ok {
+					l.text = value
+				}
+				oled.TextBox(size+4, y, displayWidth-size-4, lineHeight, l.text, line, true)
+				continue
+			}
+			oled.TextBox(2, y, displayWidth-2, lineHeight, l.text, line, true)
+		}
+		oled.SetFont(nil)
 
 	case 3:
 		oled.SetFontSize(14)
@@ -513,9 +623,32 @@ func drawNonTimePage() {
 		}
 	}
 
-	oled.Send()
 	lastPageIndex = pageIndex
 	lastShutdownSel = shutdownSelect
+	sendFrame()
+}
+
+// sendFrame flushes the screen. The first failure of a run is logged and makes the current
+// page redraw in full on the next tick, the first success after it is logged too.
+func sendFrame() {
+	err := oled.Send()
+	if (err != nil) == sendFailing {
+		return
+	}
+	sendFailing = err != nil
+	var stats string
+	if hw, ok := oled.(*nanohatoled.NanoOled); ok {
+		s := hw.IOStats()
+		stats = fmt.Sprintf(" (writes %d, retries %d, failures %d, re-inits %d)",
+			s.Writes, s.Retries, s.Failures, s.Reinits)
+	}
+	if err == nil {
+		logger.Printf("Send recovered%s", stats)
+		return
+	}
+	logger.Printf("Send failed: %v%s", err, stats)
+	staticDrawn = false
+	lastPageIndex = -1
 }
 
 // drawPage handles main OLED page rendering logic
@@ -527,26 +660,33 @@ func drawPage() {
 		return
 	}
 
-	if pageSleepCount <= 0 {
-		if pageSleepCount == 0 {
-			oled.Clear()
-			oled.Send()
-			staticDrawn = false
-			lastPageIndex = -1
-			pageSleepCount = -1
+	// Idle: dim for the last idle_dim seconds, then put the panel to sleep
+	if cfg.sleepAfter > 0 {
+		if pageSleepCount <= 0 {
+			if oled.IsOn() {
+				if err := oled.Off(); err != nil {
+					logger.Printf("Display off failed: %v", err)
+				}
+				staticDrawn = false
+				lastPageIndex = -1
+			}
+			return
+		}
+		pageSleepCount--
+		if !idleDimmed && cfg.idleDim > 0 && pageSleepCount <= cfg.idleDim {
+			idleDimmed = true
+			applyBrightness()
 		}
-		return
 	}
-	pageSleepCount--
 
 	drawing = true
 	defer func() { drawing = false }()
 
 	switch pageIndex {
 	case 0:
-		if !staticDrawn || lastPageIndex != 0 {
-			drawTimePageStatic()
+		if !staticDrawn || lastPageIndex != 0 || clockShift(time.Now()) != timeShift {
 			lastPageIndex = 0
+			drawTimePageStatic()
 		} else {
 			updateTimeOnly()
 		}
@@ -556,13 +696,96 @@ func drawPage() {
 			drawNonTimePage()
 		}
 	}
+
+	// Wake from sleep only once the page is drawn, not showing the frame from before
+	if !oled.IsOn() {
+		if err := oled.On(); err != nil {
+			logger.Printf("Display on failed: %v", err)
+		}
+	}
+}
+
+// inDimWindow reports whether now falls in the configured dim window (may wrap midnight)
+func inDimWindow(now time.Time) bool {
+	if cfg.dimStart < 0 || cfg.dimEnd < 0 || cfg.dimStart == cfg.dimEnd {
+		return false
+	}
+	minute := now.Hour()*60 + now.Minute()
+	if cfg.dimStart < cfg.dimEnd {
+		return minute >= cfg.dimStart && minute < cfg.dimEnd
+	}
+	return minute >= cfg.dimStart || minute < cfg.dimEnd
+}
+
+// initPanel applies inversion and panel tuning from config, records daytime contrast
+func initPanel() {
+	if hw, ok := oled.(*nanohatoled.NanoOled); ok {
+		if err := hw.SetInvert(cfg.invert); err != nil {
+			logger.Printf("Invert setup failed: %v", err)
+		}
+		if cfg.precharge >= 0 {
+			if err := hw.SetPrecharge(uint8(cfg.precharge&0x0f), uint8(cfg.precharge>>4)); err != nil {
+				logger.Printf("Pre-charge setup failed: %v", err)
+			}
+		}
+		if cfg.vcomh >= 0 {
+			if err := hw.SetVCOMH(byte(cfg.vcomh)); err != nil {
+				logger.Printf("VCOMH setup failed: %v", err)
+			}
+		}
+	}
+
+	dayContrast = cfg.contrast
+	if dayContrast < 0 {
+		dayContrast = int(oled.Contrast())
+	}
+	appliedContrast = int(oled.Contrast())
+	applyBrightness()
+}
+
+// applyBrightness switches contrast according to the dim schedule
+func applyBrightness() {
+	want := dayContrast
+	if inDimWindow(time.Now().In(localLoc)) {
+		want = cfg.dimContrast
+	}
+	if idleDimmed && cfg.idleContrast < want {
+		want = cfg.idleContrast
+	}
+	if want == appliedContrast {
+		return
+	}
+	if err := oled.SetContrast(uint8(want)); err != nil {
+		logger.Printf("Set contrast failed: %v", err)
+		return
+	}
+	logger.Printf("Contrast set to %d", want)
+	appliedContrast = want
+}
+
+// probePanel attaches a panel that did not answer yet, set it up and redraw the current page
+func probePanel() {
+	hw, ok := oled.(*nanohatoled.NanoOled)
+	if !ok || !hw.PanelMissing() {
+		return
+	}
+	if err := hw.Probe(); err != nil {
+		return
+	}
+	initPanel()
+	staticDrawn = false
+	lastPageIndex = -1
 }
 
 // resetSleepCount resets page sleep counter
 func resetSleepCount() {
 	pageMutex.Lock()
-	pageSleepCount = pageSleep
+	pageSleepCount = cfg.sleepAfter
 	staticDrawn = false
+	if idleDimmed {
+		idleDimmed = false
+		applyBrightness()
+	}
 	pageMutex.Unlock()
 }
 
@@ -616,23 +839,116 @@ func handleK3() {
 	}
 }
 
-// watchButtons monitors button events in goroutines
+// buttonTiming returns the button timing of the daemon
+func buttonTiming() nanohatoled.ButtonTiming {
+	// No double-click actions, so clicks need not wait for a second one
+	timing := nanohatoled.DefaultButtonTiming()
+	timing.DoubleClick = 0
+	return timing
+}
+
+// handleButton runs the page action of a button event; K1+K3 together saves a snapshot
+func handleButton(ev nanohatoled.ButtonEvent) {
+	handlers := map[int]func(){btnK1: handleK1, btnK2: handleK2, btnK3: handleK3}
+	switch {
+	case ev.Type == nanohatoled.ButtonClick && handlers[ev.Button] != nil:
+		handlers[ev.Button]()
+		drawPage()
+	case ev.Type == nanohatoled.ButtonChord && ev.Mask == nanohatoled.ButtonMask(btnK1, btnK3):
+		logger.Printf("%v", ev)
+		saveSnapshot()
+	}
+}
+
+// watchButtons feeds button events from the configured source or the GPIO buttons to handleButton
 func watchButtons() {
-	watchBtn := func(btnIdx int, handler func()) {
-		for {
-			if oled.Btn[btnIdx].WaitForEdge(-1) {
-				time.Sleep(150 * time.Millisecond)
-				handler()
-				drawPage()
-			} else {
-				time.Sleep(100 * time.Millisecond)
+	timing := buttonTiming()
+
+	var buttons *nanohatoled.ButtonManager
+	if cfg.buttonSource != nil {
+		buttons = nanohatoled.NewButtonManager(context.Background(), timing)
+		go func() {
+			if err := cfg.buttonSource.Run(context.Background(), buttons.Feed); err != nil {
+				logger.Printf("Button source failed: %v", err)
 			}
+		}()
+	} else {
+		hw, ok := oled.(*nanohatoled.NanoOled)
+		if !ok || hw.IsVirtual() {
+			logger.Println("No buttons available on virtual display")
+			return
+		}
+		buttons = hw.Buttons(context.Background(), timing)
+	}
+
+	go func() {
+		for ev := range buttons.Events() {
+			handleButton(ev)
+		}
+	}()
+}
+
+// saveSnapshot writes the current screen to snapshotPath
+func saveSnapshot() {
+	pageMutex.Lock()
+	defer pageMutex.Unlock()
+	if hw, ok := oled.(*nanohatoled.NanoOled); ok {
+		if err := hw.SaveSnapshot(snapshotPath, snapshotScale); err != nil {
+			logger.Printf("Snapshot failed: %v", err)
+		} else {
+			logger.Printf("Snapshot saved to %s", snapshotPath)
 		}
 	}
+}
 
-	go watchBtn(btnK1, handleK1)
-	go watchBtn(btnK2, handleK2)
-	go watchBtn(btnK3, handleK3)
+// watchSnapshotSignal saves current screen to snapshotPath on SIGUSR1
+func watchSnapshotSignal() {
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, syscall.SIGUSR1)
+
+	go func() {
+		for range sigCh {
+			saveSnapshot()
+		}
+	}()
+}
+
+// playLogoAnimation plays logoAnimPath as boot splash, reports false when there is none
+func playLogoAnimation() bool {
+	if _, err := os.Stat(logoAnimPath); err != nil {
+		return false
+	}
+
+	opts := nanohatoled.ImageOptions{Dither: nanohatoled.DitherBayer}
+	if cfg.logo != nil {
+		opts = *cfg.logo
+	}
+	anim, err := nanohatoled.LoadGIFFile(logoAnimPath, displayWidth, displayHeight, opts)
+	if err != nil {
+		logger.Printf("Logo animation load failed: %v", err)
+		return false
+	}
+
+	// Endless animations run for the normal splash time, finite ones to their end
+	limit := splashMaxDuration
+	if anim.Loops == 0 {
+		limit = splashDuration
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), limit)
+	defer cancel()
+	if err := oled.Play(ctx, anim, 0, 0); err != nil && err != context.DeadlineExceeded {
+		logger.Printf("Logo animation failed: %v", err)
+		return false
+	}
+	return true
+}
+
+// drawLogo draws logoPath, converted with the configured logo options if any
+func drawLogo() error {
+	if cfg.logo == nil {
+		return oled.Image(logoPath)
+	}
+	return oled.ImageWithOptions(logoPath, *cfg.logo)
 }
 
 // doShutdown executes system shutdown procedure
@@ -641,24 +957,33 @@ func doShutdown() {
 	defer pageMutex.Unlock()
 
 	logger.Println("Executing shutdown...")
-	oled.Clear()
-	oled.New(0)
+	oled.Fill(false)
 	oled.SetFontSize(14)
 	oled.SetBold(true)
 	oled.Text(2, 2, "Shutting down", true)
 	oled.SetFontSize(11)
 	oled.SetBold(false)
 	oled.Text(2, 20, "Please wait...", true)
-	oled.Send()
+	sendFrame()
 
-	time.Sleep(2 * time.Second)
+	// Spin until the poweroff delay is over
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	if err := oled.Play(ctx, nanohatoled.Spinner(spinnerSize, 0), displayWidth-spinnerSize-4, 36); err != nil && err != context.DeadlineExceeded {
+		logger.Printf("Spinner failed: %v", err)
+	}
+	<-ctx.Done()
+	cancel()
 
 	oled.Clear()
-	oled.Send()
+	sendFrame()
 	time.Sleep(300 * time.Millisecond)
 
 	os.Remove(pidFilePath)
 
+	if !cfg.poweroff {
+		logger.Println("Poweroff disabled, exiting")
+		return
+	}
 	if err := syscall.Exec("/sbin/poweroff", []string{"poweroff"}, os.Environ()); err != nil {
 		logger.Printf("Poweroff failed: %v", err)
 	}
@@ -679,16 +1004,29 @@ func main() {
 	shutdownSelect = 1
 
 	if len(os.Args) > 1 && os.Args[1] == "-stop" {
+		stopCfg, err := loadConfig(configPath)
+		if err != nil {
+			fmt.Printf("Config error, using defaults: %v\n", err)
+			stopCfg = defaultConfig()
+		}
+
 		fmt.Println("Clearing OLED screen...")
-		stopOled, err := nanohatoled.Open()
+		stopCfg.oled.NoButtons = true
+		stopOled, err := nanohatoled.OpenWithOptions(stopCfg.oled)
 		if err != nil {
 			fmt.Printf("Failed to open OLED for clear: %v\n", err)
 		} else {
 			defer stopOled.Close()
-			stopOled.Clear()
-			stopOled.Send()
-			time.Sleep(300 * time.Millisecond)
-			fmt.Println("Screen cleared successfully")
+			err := stopOled.Clear()
+			if err == nil {
+				err = stopOled.Send()
+			}
+			if err != nil {
+				fmt.Printf("Failed to clear OLED: %v\n", err)
+			} else {
+				time.Sleep(300 * time.Millisecond)
+				fmt.Println("Screen cleared successfully")
+			}
 		}
 
 		if _, err := os.Stat(pidFilePath); os.IsNotExist(err) {
@@ -717,7 +1055,7 @@ func main() {
 		}
 
 		if err := syscall.Kill(pid, syscall.SIGTERM); err != nil {
-			fmt.Printf("Failed to send SIGTERM to PID %d: %v\n", pid)
+			fmt.Printf("Failed to send SIGTERM to PID %d: %v\n", pid, err)
 			if processExists(pid) {
 				fmt.Printf("Trying to force kill PID %d...\n", pid)
 				if err := syscall.Kill(pid, syscall.SIGKILL); err != nil {
@@ -744,30 +1082,60 @@ func main() {
 	}
 
 	var err error
-	oled, err = nanohatoled.Open()
+	cfg, err = loadConfig(configPath)
 	if err != nil {
-		logger.Fatalf("OLED init failed: %v", err)
+		logger.Printf("Config error, using defaults: %v", err)
+		cfg = defaultConfig()
+	}
+
+	cfg.oled.Logger = logger
+	cfg.oled.Hotplug = true // Start before the HAT answers, probePanel attaches it
+	hwOled, err := nanohatoled.OpenWithOptions(cfg.oled)
+	var btnErr *nanohatoled.ButtonError
+	if errors.As(err, &btnErr) {
+		// Keep the panel, a configured button_source still drives the pages
+		logger.Printf("Buttons unavailable, running without them: %v", err)
+		cfg.oled.NoButtons = true
+		hwOled, err = nanohatoled.OpenWithOptions(cfg.oled)
+	}
+	if err == nil {
+		oled = hwOled
+	} else {
+		logger.Printf("OLED init failed, running headless: %v", err)
+		virtOled, err := nanohatoled.OpenVirtual(cfg.oled)
+		if err != nil {
+			logger.Fatalf("Virtual OLED init failed: %v", err)
+		}
+		oled = virtOled
 	}
 	defer oled.Close()
+	displayWidth, displayHeight = oled.Size()
+	loadInfoFont()
 
 	pageMutex.Lock()
 	logger.Println("Display logo...")
 	oled.Clear()
-	oled.New(0)
-	if _, err := os.Stat(logoPath); os.IsNotExist(err) {
-		logger.Printf("Logo not found: %s", logoPath)
-		oled.Text(2, 20, "No Logo", true)
-	} else if err := oled.Image(logoPath); err != nil {
-		logger.Printf("Logo load failed: %v", err)
-		oled.Text(2, 20, "Logo Err", true)
-	}
-	oled.Send()
+	oled.New(cfg.rotation)
+	initPanel()
+	animated := playLogoAnimation()
+	if !animated {
+		if _, err := os.Stat(logoPath); os.IsNotExist(err) {
+			logger.Printf("Logo not found: %s", logoPath)
+			oled.Text(2, 20, "No Logo", true)
+		} else if err := drawLogo(); err != nil {
+			logger.Printf("Logo load failed: %v", err)
+			oled.Text(2, 20, "Logo Err", true)
+		}
+		sendFrame()
+	}
 	pageMutex.Unlock()
-	time.Sleep(2 * time.Second)
+	if !animated {
+		time.Sleep(splashDuration)
+	}
 
 	pageMutex.Lock()
 	pageIndex = 0
-	pageSleepCount = pageSleep
+	pageSleepCount = cfg.sleepAfter
 	drawing = false
 	shutdownFlag = false
 	lastPageIndex = -1
@@ -777,12 +1145,14 @@ func main() {
 	pageMutex.Unlock()
 
 	watchButtons()
+	watchSnapshotSignal()
 
 	logger.Println("Main loop started")
 	ticker := time.NewTicker(1 * time.Second)
 	defer ticker.Stop()
 
-	for range ticker.C {
+	for tick := 1; ; tick++ {
+		<-ticker.C
 		pageMutex.Lock()
 		isShutdown := shutdownFlag
 		pageMutex.Unlock()
@@ -792,6 +1162,12 @@ func main() {
 			return
 		}
 
+		pageMutex.Lock()
+		if tick%probeInterval == 0 {
+			probePanel()
+		}
+		applyBrightness()
+		pageMutex.Unlock()
 		drawPage()
 	}
 }
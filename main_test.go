@@ -0,0 +1,97 @@
+package main
+
+import (
+	"context"
+	"io"
+	"log"
+	"testing"
+	"time"
+
+	nanohatoled "nanohat-oled/ext"
+)
+
+// setupDaemon - Daemon state as main leaves it before the main loop, on a virtual display
+func setupDaemon(t *testing.T) {
+	t.Helper()
+	localLoc = time.Local
+	logger = &LocalTimeLogger{base: log.New(io.Discard, "", 0)}
+	cfg = defaultConfig()
+	cfg.oled.FontDir = "files/NanoHatOLED"
+	virt, err := nanohatoled.OpenVirtual(cfg.oled)
+	if err != nil {
+		t.Fatal(err)
+	}
+	oled = virt
+	t.Cleanup(func() { oled.Close() })
+	displayWidth, displayHeight = oled.Size()
+	oled.New(cfg.rotation)
+	initPanel()
+
+	pageIndex = 0
+	pageSleepCount = cfg.sleepAfter
+	drawing = false
+	shutdownFlag = false
+	shutdownSelect = 0
+	lastPageIndex = -1
+	lastTimeStr = ""
+	lastShutdownSel = -1
+	staticDrawn = false
+	idleDimmed = false
+}
+
+// pressButtons - Play script through a button manager into handleButton, like watchButtons
+func pressButtons(t *testing.T, script string) {
+	t.Helper()
+	src, err := nanohatoled.ScriptSource(script)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	buttons := nanohatoled.NewButtonManager(ctx, buttonTiming())
+	done := make(chan struct{})
+	go func() {
+		for ev := range buttons.Events() {
+			handleButton(ev)
+		}
+		close(done)
+	}()
+	src.Run(ctx, buttons.Feed)
+	cancel()
+	<-done
+}
+
+func TestButtonNavigation(t *testing.T) {
+	cases := []struct {
+		script   string
+		page     int
+		selected int // Shutdown page choice, 0 is Yes
+		shutdown bool
+	}{
+		{"", 0, 0, false},
+		{"2", 1, 0, false},
+		{"2 1", 0, 0, false},
+		{"3", 3, 1, false},
+		{"3 3", 0, 1, false},
+		{"3 2", 0, 1, false},
+		{"3 1", 3, 0, false},
+		{"3 1 1 2", 0, 1, false},
+		{"3 1 2", 3, 0, true},
+		{"2 3 1 2", 3, 0, true},
+	}
+	for _, c := range cases {
+		setupDaemon(t)
+		pressButtons(t, c.script)
+		if pageIndex != c.page || shutdownSelect != c.selected || shutdownFlag != c.shutdown {
+			t.Errorf("%q: page %d, selected %d, shutdown %v; want page %d, selected %d, shutdown %v",
+				c.script, pageIndex, shutdownSelect, shutdownFlag, c.page, c.selected, c.shutdown)
+		}
+	}
+}
+
+func TestShutdownPageDrawn(t *testing.T) {
+	setupDaemon(t)
+	pressButtons(t, "3 1")
+	if lastPageIndex != 3 || lastShutdownSel != 0 {
+		t.Errorf("drawn page %d with selection %d, want page 3 with Yes selected", lastPageIndex, lastShutdownSel)
+	}
+}